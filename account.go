@@ -2,22 +2,32 @@ package gosdk
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	sdkClient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/gogo/protobuf/proto"
 	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
+// ErrTxNotPacked is returned by WaitTx when a transaction is not found in a block before the timeout.
+var ErrTxNotPacked = errors.New("tx not packed")
+
 // GetAccount retrieves account information from the chain for a given signer.
 //
 // @param signer the Signer instance to retrieve the account for
@@ -31,6 +41,24 @@ func (s *Server) GetAccount(signer Signer) (*cysicTypes.EthAccount, error) {
 // @param addr the address to retrieve the account information for
 // @return the account information as an EthAccount struct, or an error if retrieval fails
 func (s *Server) GetAccountByAddr(addr string) (*cysicTypes.EthAccount, error) {
+	account, err := s.GetAccountI(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	temp, ok := account.(*cysicTypes.EthAccount)
+	if !ok {
+		return nil, fmt.Errorf("account %v is %T, not an EthAccount", account.GetAddress().String(), account)
+	}
+
+	return temp, nil
+}
+
+// GetAccountI retrieves account information from the chain for a given address, whatever its account type.
+//
+// @param addr the address to retrieve the account information for
+// @return the account, e.g. an EthAccount or a vesting account, or an error if retrieval fails
+func (s *Server) GetAccountI(addr string) (authTypes.AccountI, error) {
 	if err := s.KeepGrpcConn(); err != nil {
 		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
 		return nil, err
@@ -44,19 +72,18 @@ func (s *Server) GetAccountByAddr(addr string) (*cysicTypes.EthAccount, error) {
 
 	client := authTypes.NewQueryClient(s.Conn)
 	req := authTypes.QueryAccountRequest{Address: cosmosAddr}
-	res, err := client.Account(context.Background(), &req)
+	res, err := client.Account(s.queryContext(), &req)
 	if err != nil {
 		return nil, err
 	}
 
-	temp := &cysicTypes.EthAccount{}
-	err = temp.XXX_Unmarshal(res.Account.GetValue())
-	if err != nil {
-		log.Printf("error when new grpc client: %s\n", err.Error())
+	var account authTypes.AccountI
+	if err := cdc.UnpackAny(res.Account, &account); err != nil {
+		log.Printf("error when unpack account: %v, type: %v, err: %v", cosmosAddr, res.Account.GetTypeUrl(), err.Error())
 		return nil, err
 	}
 
-	return temp, nil
+	return account, nil
 }
 
 // BroadcastTx broadcasts a signed transaction to the network.
@@ -88,23 +115,37 @@ func (s *Server) BroadcastTx(txBytes []byte) (*sdk.TxResponse, error) {
 }
 
 func (s *Server) getAccountNumberAndSequenceOnChain(address sdk.AccAddress) (exist bool, accNumber uint64, sequence uint64, err error) {
-	temp, err := s.GetAccountByAddr(address.String())
+	// transactions are signed for the latest state, even by a Server pinned with AtHeight
+	temp, err := s.AtHeight(0).GetAccountI(address.String())
 	if err != nil {
-		log.Printf("error when GetAccountByAddr: %v, err: %v", address.String(), err.Error())
+		log.Printf("error when GetAccountI: %v, err: %v", address.String(), err.Error())
 		return false, 0, 0, err
 	}
 
-	accNumber = temp.AccountNumber
-	sequence = temp.Sequence
+	accNumber = temp.GetAccountNumber()
+	sequence = temp.GetSequence()
 	return true, accNumber, sequence, nil
 }
 
+// defaultGasAdjustment is multiplied with the simulated gas to get the gas limit of a transaction.
+const defaultGasAdjustment = 1.3
+
 // buildAndBroadcastCosmosTx builds a Cosmos transaction with the provided signer and messages, then broadcasts it to the network.
 //
 // @param signer the Signer instance used to sign the transaction
 // @param msgList list of messages to include in the transaction
 // @return the transaction hash as a string, or an error if the transaction fails
 func (s *Server) buildAndBroadcastCosmosTx(signer Signer, msgList []sdk.Msg) (string, error) {
+	return s.buildAndBroadcastCosmosTxWithGas(signer, msgList, s.GasLimit)
+}
+
+// buildAndBroadcastCosmosTxWithGas builds a Cosmos transaction with a custom gas limit, then broadcasts it to the network.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param msgList list of messages to include in the transaction
+// @param gasLimit the gas limit of the transaction
+// @return the transaction hash as a string, or an error if the transaction fails
+func (s *Server) buildAndBroadcastCosmosTxWithGas(signer Signer, msgList []sdk.Msg, gasLimit uint64) (string, error) {
 	for _, msg := range msgList {
 		if err := msg.ValidateBasic(); err != nil {
 			log.Printf("error when validate basic for msg: %v, err: %v\n", msg, err.Error())
@@ -112,9 +153,7 @@ func (s *Server) buildAndBroadcastCosmosTx(signer Signer, msgList []sdk.Msg) (st
 		}
 	}
 
-	signerPriv := signer.privateKey
 	accAddr := signer.CosmosAddr
-	signerPubKey := signerPriv.PubKey()
 
 	exist, accNumber, sequence, err := s.getAccountNumberAndSequenceOnChain(accAddr)
 	if err != nil {
@@ -129,17 +168,44 @@ func (s *Server) buildAndBroadcastCosmosTx(signer Signer, msgList []sdk.Msg) (st
 	if signer.Nonce != 0 && signer.Nonce > sequence {
 		sequence = signer.Nonce
 	}
-	txBuilder, bytesToSign, err := s.GetBytesToSign(signer, accNumber, sequence, msgList)
+	txBytes, err := s.signTx(signer, accNumber, sequence, gasLimit, msgList)
 	if err != nil {
-		log.Printf("error when get wait sign tx, err: %v\n", err.Error())
 		return "", err
 	}
 
-	sigBytes, err := signerPriv.Sign(bytesToSign)
+	resp, err := s.BroadcastTx(txBytes)
 	if err != nil {
-		log.Printf("error when sign msg, err: %v\n", err.Error())
+		log.Printf("error when broadcast tx, err: %v\n", err.Error())
 		return "", err
 	}
+	if resp.Code != 0 {
+		log.Printf("resp code not zero, log: %v\n", resp.RawLog)
+		return "", fmt.Errorf(resp.RawLog)
+	}
+
+	return resp.TxHash, nil
+}
+
+// signTx signs the messages with the signer and returns the encoded transaction.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param accNumber the account number of the signer
+// @param sequence the sequence number of the signer
+// @param gasLimit the gas limit of the transaction
+// @param msgList list of messages to include in the transaction
+// @return the signed transaction bytes, or an error if signing fails
+func (s *Server) signTx(signer Signer, accNumber, sequence, gasLimit uint64, msgList []sdk.Msg) ([]byte, error) {
+	txBuilder, bytesToSign, err := s.getBytesToSign(signer, accNumber, sequence, gasLimit, msgList)
+	if err != nil {
+		log.Printf("error when get wait sign tx, err: %v\n", err.Error())
+		return nil, err
+	}
+
+	sigBytes, err := signer.privateKey.Sign(bytesToSign)
+	if err != nil {
+		log.Printf("error when sign msg, err: %v\n", err.Error())
+		return nil, err
+	}
 
 	// Construct the SignatureV2 struct
 	sigData := signing.SingleSignatureData{
@@ -147,7 +213,7 @@ func (s *Server) buildAndBroadcastCosmosTx(signer Signer, msgList []sdk.Msg) (st
 		Signature: sigBytes,
 	}
 	sig := signing.SignatureV2{
-		PubKey:   signerPubKey,
+		PubKey:   signer.privateKey.PubKey(),
 		Data:     &sigData,
 		Sequence: sequence,
 	}
@@ -155,26 +221,181 @@ func (s *Server) buildAndBroadcastCosmosTx(signer Signer, msgList []sdk.Msg) (st
 	err = txBuilder.SetSignatures(sig)
 	if err != nil {
 		log.Printf("error when set signed bytes to tx, err: %v\n", err.Error())
-		return "", err
+		return nil, err
 	}
 
 	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
 	if err != nil {
 		log.Printf("error when get signed tx bytes, err: %v\n", err.Error())
-		return "", err
+		return nil, err
 	}
 
-	resp, err := s.BroadcastTx(txBytes)
+	return txBytes, nil
+}
+
+// SimulateTx simulates a transaction with the provided signer and messages.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param msgList list of messages to include in the transaction
+// @return the gas used by the simulation, or an error if the simulation fails
+func (s *Server) SimulateTx(signer Signer, msgList []sdk.Msg) (uint64, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return 0, err
+	}
+
+	for _, msg := range msgList {
+		if err := msg.ValidateBasic(); err != nil {
+			log.Printf("error when validate basic for msg: %v, err: %v\n", msg, err.Error())
+			return 0, err
+		}
+	}
+
+	_, accNumber, sequence, err := s.getAccountNumberAndSequenceOnChain(signer.CosmosAddr)
 	if err != nil {
-		log.Printf("error when broadcast tx, err: %v\n", err.Error())
-		return "", err
+		log.Printf("error when get accInfo on chain, addr: %v, err: %v\n", signer.CosmosAddr.String(), err.Error())
+		return 0, err
 	}
-	if resp.Code != 0 {
-		log.Printf("resp code not zero, log: %v\n", resp.RawLog)
-		return "", fmt.Errorf(resp.RawLog)
+	if signer.Nonce != 0 && signer.Nonce > sequence {
+		sequence = signer.Nonce
 	}
 
-	return resp.TxHash, nil
+	txBytes, err := s.signTx(signer, accNumber, sequence, s.GasLimit, msgList)
+	if err != nil {
+		return 0, err
+	}
+
+	client := sdkTx.NewServiceClient(s.Conn)
+	resp, err := client.Simulate(context.Background(), &sdkTx.SimulateRequest{TxBytes: txBytes})
+	if err != nil {
+		log.Printf("error when simulate tx, err: %v\n", err.Error())
+		return 0, err
+	}
+
+	return resp.GasInfo.GasUsed, nil
+}
+
+// GetTx retrieves the result of a transaction by hash.
+//
+// @param txHash the hash of the transaction
+// @return the transaction response, or an error if the transaction is not found
+func (s *Server) GetTx(txHash string) (*sdk.TxResponse, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := sdkTx.NewServiceClient(s.Conn)
+	resp, err := client.GetTx(context.Background(), &sdkTx.GetTxRequest{Hash: txHash})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.TxResponse, nil
+}
+
+// WaitTx waits for a transaction to be packed into a block and returns its result.
+//
+// @param txHash the hash of the transaction to wait for
+// @param timeout the maximum time to wait
+// @return the transaction response, or ErrTxNotPacked if the transaction is not packed before timeout
+func (s *Server) WaitTx(txHash string, timeout time.Duration) (*sdk.TxResponse, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := s.GetTx(txHash)
+		if err == nil && resp != nil && resp.Height != 0 {
+			return resp, nil
+		}
+		if err != nil && !isTxNotFound(err) {
+			log.Printf("error when get tx: %v, err: %v", txHash, err.Error())
+			return nil, err
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("tx %v not packed after %v: %w", txHash, timeout, ErrTxNotPacked)
+		}
+		<-time.NewTimer(time.Second).C
+	}
+}
+
+// isTxNotFound reports whether err means the transaction is not (yet) indexed by the node.
+func isTxNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound || strings.Contains(err.Error(), "tx not found")
+}
+
+// DecodeMsgResponse decodes the response of a message from the result of a transaction.
+//
+// @param txResp the transaction response, e.g. returned by WaitTx
+// @param msgIndex the index of the message in the transaction
+// @param response the response to decode into, e.g. a *banktypes.MsgSendResponse
+// @return an error if the transaction failed or has no response for msgIndex
+func DecodeMsgResponse(txResp *sdk.TxResponse, msgIndex int, response codec.ProtoMarshaler) error {
+	if txResp.Code != 0 {
+		return fmt.Errorf("tx %v failed, log: %v", txResp.TxHash, txResp.RawLog)
+	}
+
+	data, err := hex.DecodeString(txResp.Data)
+	if err != nil {
+		return fmt.Errorf("invalid data of tx %v, err: %v", txResp.TxHash, err)
+	}
+
+	var msgData sdk.TxMsgData
+	if err := msgData.Unmarshal(data); err != nil {
+		return fmt.Errorf("invalid data of tx %v, err: %v", txResp.TxHash, err)
+	}
+
+	var value []byte
+	switch {
+	case msgIndex >= 0 && msgIndex < len(msgData.MsgResponses):
+		value = msgData.MsgResponses[msgIndex].Value
+	case msgIndex >= 0 && msgIndex < len(msgData.Data): //nolint: staticcheck
+		// chains before v0.46 only fill the deprecated Data field
+		value = msgData.Data[msgIndex].Data //nolint: staticcheck
+	default:
+		return fmt.Errorf("tx %v has no response for msg %v", txResp.TxHash, msgIndex)
+	}
+
+	return response.Unmarshal(value)
+}
+
+// DecodeTypedEvent decodes the first typed event of the type of event emitted by a transaction.
+//
+// @param txResp the transaction response, e.g. returned by WaitTx
+// @param event the event to decode into, e.g. a *govTokenTypes.EventMint
+// @return an error if the transaction emitted no such event
+func DecodeTypedEvent(txResp *sdk.TxResponse, event proto.Message) error {
+	eventType := proto.MessageName(event)
+
+	eventList := make([]abci.Event, 0, len(txResp.Events))
+	eventList = append(eventList, txResp.Events...)
+	// nodes that don't fill Events still have the events in the logs
+	for _, msgLog := range txResp.Logs {
+		for _, stringEvent := range msgLog.Events {
+			abciEvent := abci.Event{Type: stringEvent.Type}
+			for _, attr := range stringEvent.Attributes {
+				abciEvent.Attributes = append(abciEvent.Attributes, abci.EventAttribute{Key: []byte(attr.Key), Value: []byte(attr.Value)})
+			}
+			eventList = append(eventList, abciEvent)
+		}
+	}
+
+	for _, abciEvent := range eventList {
+		if abciEvent.Type != eventType {
+			continue
+		}
+
+		typedEvent, err := sdk.ParseTypedEvent(abciEvent)
+		if err != nil {
+			return err
+		}
+		bz, err := proto.Marshal(typedEvent)
+		if err != nil {
+			return err
+		}
+		return proto.Unmarshal(bz, event)
+	}
+
+	return fmt.Errorf("tx %v has no %v event", txResp.TxHash, eventType)
 }
 
 // waitTxPacked waits for a transaction to be packed into a block.
@@ -220,6 +441,10 @@ func (s *Server) waitTxPacked(txHash string) {
 // @param msgList list of messages to include in the transaction
 // @return the transaction builder, bytes to sign, or an error if generation fails
 func (s *Server) GetBytesToSign(signer Signer, accNumber, sequence uint64, msgList []sdk.Msg) (sdkClient.TxBuilder, []byte, error) {
+	return s.getBytesToSign(signer, accNumber, sequence, s.GasLimit, msgList)
+}
+
+func (s *Server) getBytesToSign(signer Signer, accNumber, sequence, gasLimit uint64, msgList []sdk.Msg) (sdkClient.TxBuilder, []byte, error) {
 	for _, msg := range msgList {
 		if err := msg.ValidateBasic(); err != nil {
 			log.Printf("error when validate basic for msg: %v, err: %v\n", msg, err.Error())
@@ -237,11 +462,11 @@ func (s *Server) GetBytesToSign(signer Signer, accNumber, sequence uint64, msgLi
 	fees := make(sdk.Coins, 1)
 	fees[0] = sdk.NewCoin(
 		s.GasCoin,
-		sdk.NewDec(s.GasPrice*int64(s.GasLimit)).Ceil().RoundInt(),
+		sdk.NewDec(s.GasPrice*int64(gasLimit)).Ceil().RoundInt(),
 	)
 
 	txBuilder.SetFeeAmount(fees)
-	txBuilder.SetGasLimit(s.GasLimit)
+	txBuilder.SetGasLimit(gasLimit)
 	txBuilder.SetFeePayer(signer.CosmosAddr)
 
 	signerData := authSigning.SignerData{
@@ -0,0 +1,191 @@
+package gosdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+
+	"github.com/hack2fun/gosdk/crypto/ethsecp256k1"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ADR036MsgType is the amino type of the ADR-036 arbitrary data message.
+const ADR036MsgType = "sign/MsgSignData"
+
+// ADR036PubKey is the amino JSON representation of the public key in an ADR-036 signature.
+type ADR036PubKey struct {
+	Type  string `json:"type"`
+	Value []byte `json:"value"`
+}
+
+// ADR036Signature is an ADR-036 signature in the same layout as Keplr's signArbitrary result.
+type ADR036Signature struct {
+	PubKey    ADR036PubKey `json:"pub_key"`
+	Signature []byte       `json:"signature"`
+}
+
+type adr036MsgValue struct {
+	Data   string `json:"data"`
+	Signer string `json:"signer"`
+}
+
+type adr036Msg struct {
+	Type  string         `json:"type"`
+	Value adr036MsgValue `json:"value"`
+}
+
+type adr036Fee struct {
+	Amount []sdk.Coin `json:"amount"`
+	Gas    string     `json:"gas"`
+}
+
+type adr036SignDoc struct {
+	AccountNumber string      `json:"account_number"`
+	ChainID       string      `json:"chain_id"`
+	Fee           adr036Fee   `json:"fee"`
+	Memo          string      `json:"memo"`
+	Msgs          []adr036Msg `json:"msgs"`
+	Sequence      string      `json:"sequence"`
+}
+
+// GetADR036SignBytes builds the ADR-036 amino JSON sign doc for arbitrary data.
+//
+// The sign doc has an empty chain ID, zero account number, sequence and fee, and a single
+// sign/MsgSignData message carrying the base64 encoded data.
+//
+// @param signer the 0x or cysic address of the signer
+// @param data the arbitrary data to sign
+// @return the sorted sign doc bytes, or an error if the address is invalid
+func GetADR036SignBytes(signer string, data []byte) ([]byte, error) {
+	cysicAddr, err := ConvertToCysicAddress(signer)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmos addr, err: %v", signer, err.Error())
+		return nil, err
+	}
+
+	doc := adr036SignDoc{
+		AccountNumber: "0",
+		ChainID:       "",
+		Fee: adr036Fee{
+			Amount: []sdk.Coin{},
+			Gas:    "0",
+		},
+		Memo: "",
+		Msgs: []adr036Msg{{
+			Type: ADR036MsgType,
+			Value: adr036MsgValue{
+				Data:   base64.StdEncoding.EncodeToString(data),
+				Signer: cysicAddr,
+			},
+		}},
+		Sequence: "0",
+	}
+
+	bz, err := json.Marshal(doc)
+	if err != nil {
+		log.Printf("error when marshal adr036 sign doc, err: %v", err.Error())
+		return nil, err
+	}
+
+	return sdk.SortJSON(bz)
+}
+
+// SignADR036 signs arbitrary data as an ADR-036 off-chain message.
+//
+// @param s the Signer instance
+// @param data the arbitrary data to sign
+// @return the signature with the signer's public key, or an error if signing fails
+func (s *Signer) SignADR036(data []byte) (*ADR036Signature, error) {
+	signBytes, err := GetADR036SignBytes(s.CosmosAddr.String(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.privateKey.Sign(signBytes)
+	if err != nil {
+		log.Printf("error when sign adr036 doc, err: %v", err.Error())
+		return nil, err
+	}
+
+	return &ADR036Signature{
+		PubKey: ADR036PubKey{
+			Type:  ethsecp256k1.PubKeyName,
+			Value: s.publicKey.Bytes(),
+		},
+		// cosmos wallets use the [R || S] format without the recovery id
+		Signature: sig[:crypto.RecoveryIDOffset],
+	}, nil
+}
+
+// VerifyADR036Signature verifies an ADR-036 signature of arbitrary data.
+//
+// @param signer the 0x or cysic address that claims to have signed the data
+// @param data the data that was signed
+// @param pubKey the public key of the signer
+// @param sig the signature to verify, in [R || S] or [R || S || V] format
+// @return true if the public key belongs to signer and the signature is valid, false otherwise
+func VerifyADR036Signature(signer string, data []byte, pubKey *ethsecp256k1.PubKey, sig []byte) bool {
+	if pubKey == nil {
+		return false
+	}
+
+	cysicAddr, err := ConvertToCysicAddress(signer)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmos addr, err: %v", signer, err.Error())
+		return false
+	}
+	signerAddr, err := sdk.AccAddressFromBech32(cysicAddr)
+	if err != nil {
+		log.Printf("error when convert addr: %v to accAddr, err: %v", cysicAddr, err.Error())
+		return false
+	}
+	if !bytes.Equal(signerAddr, pubKey.Address()) {
+		log.Printf("adr036 pubkey not match signer: %v", cysicAddr)
+		return false
+	}
+
+	signBytes, err := GetADR036SignBytes(cysicAddr, data)
+	if err != nil {
+		return false
+	}
+
+	return pubKey.VerifySignature(signBytes, sig)
+}
+
+// VerifyADR036StdSignature verifies an ADR-036 signature as returned by SignADR036 or Keplr's signArbitrary.
+//
+// @param signer the 0x or cysic address that claims to have signed the data
+// @param data the data that was signed
+// @param sig the signature with the signer's public key
+// @return true if the signature is valid, false otherwise
+func VerifyADR036StdSignature(signer string, data []byte, sig *ADR036Signature) bool {
+	if sig == nil {
+		return false
+	}
+	if len(sig.PubKey.Value) != ethsecp256k1.PubKeySize {
+		log.Printf("invalid adr036 pubkey size: %v", len(sig.PubKey.Value))
+		return false
+	}
+
+	pubKey := &ethsecp256k1.PubKey{Key: sig.PubKey.Value}
+	return VerifyADR036Signature(signer, data, pubKey, sig.Signature)
+}
+
+// VerifyADR036Signature verifies an ADR-036 signature of arbitrary data for the signer.
+//
+// @param s the Signer instance
+// @param data the data that was signed
+// @param sig the signature to verify
+// @return true if the signature is valid, false otherwise
+func (s *Signer) VerifyADR036Signature(data []byte, sig []byte) bool {
+	pubKey, ok := s.publicKey.(*ethsecp256k1.PubKey)
+	if !ok {
+		log.Printf("unsupported pubkey type: %T", s.publicKey)
+		return false
+	}
+
+	return VerifyADR036Signature(s.CosmosAddr.String(), data, pubKey, sig)
+}
@@ -0,0 +1,419 @@
+package gosdk
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// Year is the duration APRs are expressed over.
+const Year = 365 * 24 * time.Hour
+
+const defaultAPRBlockSample = 1000
+
+// StakingAPR is the staking reward rate of the chain, derived from the mint, distribution and staking modules.
+type StakingAPR struct {
+	Height    int64  `json:"height"`
+	MintDenom string `json:"mint_denom"`
+	BondDenom string `json:"bond_denom"`
+	// MintToBondRate is the amount of BondDenom one MintDenom exchanges to, 1 when both are the same
+	MintToBondRate   sdk.Dec  `json:"mint_to_bond_rate"`
+	Inflation        sdk.Dec  `json:"inflation"`
+	AnnualProvisions sdk.Dec  `json:"annual_provisions"`
+	CommunityTax     sdk.Dec  `json:"community_tax"`
+	BondedTokens     math.Int `json:"bonded_tokens"`
+	// TotalSupply is the supply of BondDenom
+	TotalSupply math.Int `json:"total_supply"`
+	BondedRatio sdk.Dec  `json:"bonded_ratio"`
+	// BlocksPerYear is the number of blocks per year the mint module assumes
+	BlocksPerYear uint64 `json:"blocks_per_year"`
+	// ActualBlocksPerYear is the number of blocks per year at the measured block time
+	ActualBlocksPerYear sdk.Dec `json:"actual_blocks_per_year"`
+	// NominalAPR is the reward rate of a delegation before commission, assuming BlocksPerYear
+	NominalAPR sdk.Dec `json:"nominal_apr"`
+	// EffectiveAPR is NominalAPR adjusted to the measured block time
+	EffectiveAPR sdk.Dec `json:"effective_apr"`
+}
+
+// ValidatorAPR is the reward rate of a delegation to a validator, after commission.
+type ValidatorAPR struct {
+	Validator      string  `json:"validator"`
+	Moniker        string  `json:"moniker"`
+	CommissionRate sdk.Dec `json:"commission_rate"`
+	NominalAPR     sdk.Dec `json:"nominal_apr"`
+	EffectiveAPR   sdk.Dec `json:"effective_apr"`
+}
+
+// RewardProjection is the reward expected from a delegation over a horizon, without compounding.
+type RewardProjection struct {
+	Amount  math.Int      `json:"amount"`
+	Horizon time.Duration `json:"horizon"`
+	APR     sdk.Dec       `json:"apr"`
+	Rewards sdk.Dec       `json:"rewards"`
+}
+
+// RewardSample is the pending reward and delegation of a delegator to a validator at a point in time,
+// both in the bond denom.
+type RewardSample struct {
+	Delegator string    `json:"delegator"`
+	Validator string    `json:"validator"`
+	Height    int64     `json:"height"`
+	Time      time.Time `json:"time"`
+	Delegated math.Int  `json:"delegated"`
+	Rewards   sdk.Dec   `json:"rewards"`
+}
+
+// RewardComparison compares the rewards accrued between two samples with their projection.
+type RewardComparison struct {
+	Elapsed   time.Duration `json:"elapsed"`
+	Accrued   sdk.Dec       `json:"accrued"`
+	Projected sdk.Dec       `json:"projected"`
+	// RealizedAPR is the APR the accrued rewards correspond to
+	RealizedAPR sdk.Dec `json:"realized_apr"`
+	// Difference is Accrued minus Projected
+	Difference sdk.Dec `json:"difference"`
+}
+
+// GetMintParams retrieves the parameters of the mint module.
+//
+// @return the mint parameters, or an error if the query fails
+func (s *Server) GetMintParams() (*minttypes.Params, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := minttypes.NewQueryClient(s.Conn)
+	resp, err := client.Params(s.queryContext(), &minttypes.QueryParamsRequest{})
+	if err != nil {
+		log.Printf("could not query mint params: %v", err)
+		return nil, err
+	}
+
+	return &resp.Params, nil
+}
+
+// GetInflation retrieves the current inflation rate of the mint module.
+//
+// @return the inflation rate, or an error if the query fails
+func (s *Server) GetInflation() (sdk.Dec, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return sdk.Dec{}, err
+	}
+
+	client := minttypes.NewQueryClient(s.Conn)
+	resp, err := client.Inflation(s.queryContext(), &minttypes.QueryInflationRequest{})
+	if err != nil {
+		log.Printf("could not query inflation: %v", err)
+		return sdk.Dec{}, err
+	}
+
+	return resp.Inflation, nil
+}
+
+// GetAnnualProvisions retrieves the tokens the mint module currently expects to mint over a year.
+//
+// @return the annual provisions in base units of the mint denom, or an error if the query fails
+func (s *Server) GetAnnualProvisions() (sdk.Dec, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return sdk.Dec{}, err
+	}
+
+	client := minttypes.NewQueryClient(s.Conn)
+	resp, err := client.AnnualProvisions(s.queryContext(), &minttypes.QueryAnnualProvisionsRequest{})
+	if err != nil {
+		log.Printf("could not query annual provisions: %v", err)
+		return sdk.Dec{}, err
+	}
+
+	return resp.AnnualProvisions, nil
+}
+
+// GetDistributionParams retrieves the parameters of the distribution module, e.g. the community tax.
+//
+// @return the distribution parameters, or an error if the query fails
+func (s *Server) GetDistributionParams() (*distributiontypes.Params, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	resp, err := client.Params(s.queryContext(), &distributiontypes.QueryParamsRequest{})
+	if err != nil {
+		log.Printf("could not query distribution params: %v", err)
+		return nil, err
+	}
+
+	return &resp.Params, nil
+}
+
+// GetStakingAPR derives the staking reward rate of the chain.
+//
+// The nominal APR is the annual provisions net of the community tax, shared by the bonded tokens.
+// Provisions are minted in the mint denom while the bonded tokens are in the bond denom, when they
+// differ the provisions are valued in the bond denom at the govtoken exchange rate. The mint module
+// assumes BlocksPerYear blocks per year, the effective APR scales the nominal APR by the blocks per
+// year actually produced at the average block time of the last blockSample blocks.
+//
+// @param blockSample the number of blocks the block time is averaged over, 0 uses 1000
+// @return the staking APR, or an error if a query fails, nothing is bonded or the denoms have no exchange rate
+func (s *Server) GetStakingAPR(blockSample int64) (*StakingAPR, error) {
+	if blockSample <= 0 {
+		blockSample = defaultAPRBlockSample
+	}
+
+	height := s.Height()
+	if height <= 0 {
+		latest, err := s.GetLatestBlockHeight()
+		if err != nil {
+			return nil, err
+		}
+		height = latest
+	}
+	// every query reads the same height
+	pinned := s.AtHeight(height)
+
+	mintParams, err := pinned.GetMintParams()
+	if err != nil {
+		return nil, err
+	}
+	inflation, err := pinned.GetInflation()
+	if err != nil {
+		return nil, err
+	}
+	annualProvisions, err := pinned.GetAnnualProvisions()
+	if err != nil {
+		return nil, err
+	}
+	distributionParams, err := pinned.GetDistributionParams()
+	if err != nil {
+		return nil, err
+	}
+	stakingParams, err := pinned.GetStakingParams()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pinned.GetStakingPool()
+	if err != nil {
+		return nil, err
+	}
+	supply, err := pinned.GetSupplyOf(stakingParams.BondDenom)
+	if err != nil {
+		return nil, err
+	}
+	mintToBondRate := sdk.OneDec()
+	if mintParams.MintDenom != stakingParams.BondDenom {
+		rate, err := pinned.GetExchangeRate(mintParams.MintDenom, stakingParams.BondDenom)
+		if err != nil {
+			return nil, fmt.Errorf("can't value %v provisions in bond denom %v: %v", mintParams.MintDenom, stakingParams.BondDenom, err)
+		}
+		mintToBondRate, err = sdk.NewDecFromStr(rate.StringFixed(sdk.Precision))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !pool.BondedTokens.IsPositive() {
+		return nil, fmt.Errorf("no bonded tokens at height %v", height)
+	}
+
+	blockTime, err := s.GetAverageBlockTime(height, blockSample)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StakingAPR{
+		Height:              height,
+		MintDenom:           mintParams.MintDenom,
+		BondDenom:           stakingParams.BondDenom,
+		MintToBondRate:      mintToBondRate,
+		Inflation:           inflation,
+		AnnualProvisions:    annualProvisions,
+		CommunityTax:        distributionParams.CommunityTax,
+		BondedTokens:        pool.BondedTokens,
+		TotalSupply:         supply.Amount,
+		BondedRatio:         sdk.ZeroDec(),
+		BlocksPerYear:       mintParams.BlocksPerYear,
+		ActualBlocksPerYear: sdk.NewDec(int64(Year)).QuoInt64(int64(blockTime)),
+	}
+	if supply.Amount.IsPositive() {
+		result.BondedRatio = sdk.NewDecFromInt(pool.BondedTokens).QuoInt(supply.Amount)
+	}
+
+	result.NominalAPR = annualProvisions.Mul(mintToBondRate).Mul(sdk.OneDec().Sub(distributionParams.CommunityTax)).QuoInt(pool.BondedTokens)
+	result.EffectiveAPR = result.NominalAPR
+	if mintParams.BlocksPerYear > 0 {
+		result.EffectiveAPR = result.NominalAPR.Mul(result.ActualBlocksPerYear).QuoInt64(int64(mintParams.BlocksPerYear))
+	}
+
+	return result, nil
+}
+
+// GetAverageBlockTime measures the average time between blocks over a range of heights.
+//
+// @param height the last height of the range
+// @param blockSample the number of blocks in the range
+// @return the average block time, or an error if a block can't be queried
+func (s *Server) GetAverageBlockTime(height int64, blockSample int64) (time.Duration, error) {
+	fromHeight := height - blockSample
+	if fromHeight < 1 {
+		fromHeight = 1
+	}
+	if fromHeight >= height {
+		return 0, fmt.Errorf("not enough blocks before height %v", height)
+	}
+
+	fromBlock, err := s.GetBlockByHeight(fromHeight)
+	if err != nil {
+		return 0, err
+	}
+	toBlock, err := s.GetBlockByHeight(height)
+	if err != nil {
+		return 0, err
+	}
+
+	elapsed := toBlock.Header.Time.Sub(fromBlock.Header.Time)
+	if elapsed <= 0 {
+		return 0, fmt.Errorf("block time between heights %v and %v is not positive", fromHeight, height)
+	}
+
+	return elapsed / time.Duration(height-fromHeight), nil
+}
+
+// ValidatorAPR returns the reward rate of a delegation to a validator with a given commission rate.
+//
+// @param commissionRate the commission rate of the validator
+// @return the nominal and effective APR after commission
+func (a *StakingAPR) ValidatorAPR(commissionRate sdk.Dec) (sdk.Dec, sdk.Dec) {
+	share := sdk.OneDec().Sub(commissionRate)
+	return a.NominalAPR.Mul(share), a.EffectiveAPR.Mul(share)
+}
+
+// GetValidatorAPRs computes the reward rate of a delegation to each bonded validator, only bonded validators earn rewards.
+// The validator set and commissions are read at the height of apr.
+//
+// @param apr the staking APR of the chain
+// @return the validator APRs, or an error if the query fails
+func (s *Server) GetValidatorAPRs(apr *StakingAPR) ([]ValidatorAPR, error) {
+	validatorList, err := s.AtHeight(apr.Height).GetValidatorsByStatus(stakingtypes.BondStatusBonded)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ValidatorAPR, 0, len(validatorList))
+	for _, validator := range validatorList {
+		nominal, effective := apr.ValidatorAPR(validator.Commission.Rate)
+		result = append(result, ValidatorAPR{
+			Validator:      validator.OperatorAddress,
+			Moniker:        validator.Description.Moniker,
+			CommissionRate: validator.Commission.Rate,
+			NominalAPR:     nominal,
+			EffectiveAPR:   effective,
+		})
+	}
+
+	return result, nil
+}
+
+// ProjectRewards projects the reward of a delegation over a horizon at a given APR, without compounding.
+//
+// @param amount the delegated amount in base units
+// @param horizon the duration of the projection
+// @param apr the APR of the delegation, e.g. the effective APR of ValidatorAPR
+// @return the projection
+func ProjectRewards(amount math.Int, horizon time.Duration, apr sdk.Dec) *RewardProjection {
+	return &RewardProjection{
+		Amount:  amount,
+		Horizon: horizon,
+		APR:     apr,
+		Rewards: sdk.NewDecFromInt(amount).Mul(apr).MulInt64(int64(horizon)).QuoInt64(int64(Year)),
+	}
+}
+
+// SampleDelegationRewards records the pending reward and delegation of a delegator to a validator.
+//
+// Rewards are read like QueryDelegateReward, as exact decimals of the mint denom, and valued in the
+// bond denom at the rate of apr so they compare with the delegated amount.
+//
+// @param delegatorAddress the address of the delegator
+// @param validatorAddress the address of the validator
+// @param apr the staking APR providing the mint and bond denoms
+// @return the sample, or an error if a query fails
+func (s *Server) SampleDelegationRewards(delegatorAddress string, validatorAddress string, apr *StakingAPR) (*RewardSample, error) {
+	height := s.Height()
+	if height <= 0 {
+		latest, err := s.GetLatestBlockHeight()
+		if err != nil {
+			return nil, err
+		}
+		height = latest
+	}
+	block, err := s.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	pinned := s.AtHeight(height)
+
+	delegations, err := pinned.QueryDelegatorDelegations(delegatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	rewards, _, err := pinned.QueryDelegateRewardDec(delegatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RewardSample{
+		Delegator: delegatorAddress,
+		Validator: validatorAddress,
+		Height:    height,
+		Time:      block.Header.Time,
+		Delegated: sdk.NewCoins(delegations[validatorAddress]...).AmountOf(apr.BondDenom),
+		Rewards:   rewards[validatorAddress].AmountOf(apr.MintDenom).Mul(apr.MintToBondRate),
+	}, nil
+}
+
+// CompareRewards compares the rewards accrued between two samples with the projection at a given APR.
+//
+// The delegation of the first sample is used for the projection. Rewards must not have been withdrawn
+// between the samples, which also happens when the delegation changes.
+//
+// @param from the earlier sample
+// @param to the later sample
+// @param apr the projected APR
+// @return the comparison, or an error if the samples don't follow each other or rewards were withdrawn
+func CompareRewards(from *RewardSample, to *RewardSample, apr sdk.Dec) (*RewardComparison, error) {
+	if from.Delegator != to.Delegator || from.Validator != to.Validator {
+		return nil, fmt.Errorf("samples are of different delegations")
+	}
+	elapsed := to.Time.Sub(from.Time)
+	if elapsed <= 0 {
+		return nil, fmt.Errorf("sample at height %v is not after height %v", to.Height, from.Height)
+	}
+	accrued := to.Rewards.Sub(from.Rewards)
+	if accrued.IsNegative() {
+		return nil, fmt.Errorf("rewards decreased from %v to %v, they were withdrawn between the samples", from.Rewards, to.Rewards)
+	}
+
+	projected := ProjectRewards(from.Delegated, elapsed, apr).Rewards
+	result := &RewardComparison{
+		Elapsed:     elapsed,
+		Accrued:     accrued,
+		Projected:   projected,
+		RealizedAPR: sdk.ZeroDec(),
+		Difference:  accrued.Sub(projected),
+	}
+	if from.Delegated.IsPositive() {
+		result.RealizedAPR = accrued.MulInt64(int64(Year)).QuoInt(from.Delegated).QuoInt64(int64(elapsed))
+	}
+
+	return result, nil
+}
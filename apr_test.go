@@ -0,0 +1,120 @@
+package gosdk
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestProjectRewards(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  math.Int
+		horizon time.Duration
+		apr     sdk.Dec
+		want    sdk.Dec
+	}{
+		{name: "one year", amount: math.NewInt(1000), horizon: Year, apr: sdk.MustNewDecFromStr("0.1"), want: sdk.NewDec(100)},
+		{name: "half a year", amount: math.NewInt(1000), horizon: Year / 2, apr: sdk.MustNewDecFromStr("0.1"), want: sdk.NewDec(50)},
+		{name: "one day", amount: math.NewInt(365), horizon: 24 * time.Hour, apr: sdk.OneDec(), want: sdk.OneDec()},
+		{name: "zero apr", amount: math.NewInt(1000), horizon: Year, apr: sdk.ZeroDec(), want: sdk.ZeroDec()},
+		{name: "zero amount", amount: math.ZeroInt(), horizon: Year, apr: sdk.MustNewDecFromStr("0.1"), want: sdk.ZeroDec()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ProjectRewards(tt.amount, tt.horizon, tt.apr)
+			if !got.Rewards.Equal(tt.want) {
+				t.Errorf("ProjectRewards() rewards = %v, want %v", got.Rewards, tt.want)
+			}
+			if !got.Amount.Equal(tt.amount) || got.Horizon != tt.horizon || !got.APR.Equal(tt.apr) {
+				t.Errorf("ProjectRewards() = %+v, inputs not kept", got)
+			}
+		})
+	}
+}
+
+func TestCompareRewards(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(validator string, at time.Duration, delegated int64, rewards string) *RewardSample {
+		return &RewardSample{
+			Delegator: "delegator",
+			Validator: validator,
+			Height:    int64(at / BlockTime),
+			Time:      start.Add(at),
+			Delegated: math.NewInt(delegated),
+			Rewards:   sdk.MustNewDecFromStr(rewards),
+		}
+	}
+	apr := sdk.MustNewDecFromStr("0.1")
+
+	tests := []struct {
+		name           string
+		from           *RewardSample
+		to             *RewardSample
+		wantAccrued    sdk.Dec
+		wantProjected  sdk.Dec
+		wantRealized   sdk.Dec
+		wantDifference sdk.Dec
+		wantErr        bool
+	}{
+		{
+			name:           "on projection",
+			from:           sample("valA", 0, 1000, "5"),
+			to:             sample("valA", Year/10, 1000, "15"),
+			wantAccrued:    sdk.NewDec(10),
+			wantProjected:  sdk.NewDec(10),
+			wantRealized:   apr,
+			wantDifference: sdk.ZeroDec(),
+		},
+		{
+			name:           "below projection",
+			from:           sample("valA", 0, 1000, "0"),
+			to:             sample("valA", Year/10, 1000, "8"),
+			wantAccrued:    sdk.NewDec(8),
+			wantProjected:  sdk.NewDec(10),
+			wantRealized:   sdk.MustNewDecFromStr("0.08"),
+			wantDifference: sdk.NewDec(-2),
+		},
+		{
+			name:           "no delegation",
+			from:           sample("valA", 0, 0, "1"),
+			to:             sample("valA", Year/10, 0, "1"),
+			wantAccrued:    sdk.ZeroDec(),
+			wantProjected:  sdk.ZeroDec(),
+			wantRealized:   sdk.ZeroDec(),
+			wantDifference: sdk.ZeroDec(),
+		},
+		{name: "different validators", from: sample("valA", 0, 1000, "0"), to: sample("valB", Year/10, 1000, "1"), wantErr: true},
+		{name: "samples out of order", from: sample("valA", Year/10, 1000, "0"), to: sample("valA", 0, 1000, "1"), wantErr: true},
+		{name: "rewards withdrawn", from: sample("valA", 0, 1000, "10"), to: sample("valA", Year/10, 1000, "1"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareRewards(tt.from, tt.to, apr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompareRewards() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Elapsed != tt.to.Time.Sub(tt.from.Time) {
+				t.Errorf("elapsed = %v, want %v", got.Elapsed, tt.to.Time.Sub(tt.from.Time))
+			}
+			if !got.Accrued.Equal(tt.wantAccrued) {
+				t.Errorf("accrued = %v, want %v", got.Accrued, tt.wantAccrued)
+			}
+			if !got.Projected.Equal(tt.wantProjected) {
+				t.Errorf("projected = %v, want %v", got.Projected, tt.wantProjected)
+			}
+			if !got.RealizedAPR.Equal(tt.wantRealized) {
+				t.Errorf("realized apr = %v, want %v", got.RealizedAPR, tt.wantRealized)
+			}
+			if !got.Difference.Equal(tt.wantDifference) {
+				t.Errorf("difference = %v, want %v", got.Difference, tt.wantDifference)
+			}
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package gosdk
 
 import (
-	"context"
 	"fmt"
-	"github.com/shopspring/decimal"
 	"log"
 
+	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+
 	sdkmath "cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 )
 
@@ -26,7 +27,15 @@ func (s *Server) GetBalance(address string, coin string) (string, error) {
 
 	exist, amount := coins.Find(coin)
 	if exist {
-		result = decimal.NewFromBigInt(amount.Amount.BigInt(), -18).String()
+		exponent := uint32(cysicTypes.BaseDenomUnit)
+		unit, err := s.displayUnit(coin)
+		if err != nil {
+			log.Printf("error when get denom unit: %v, use default exponent, err: %v", coin, err.Error())
+		} else {
+			exponent = unit.Exponent
+		}
+
+		result = FromBaseUnits(amount.Amount, exponent).String()
 	}
 
 	return result, nil
@@ -50,15 +59,212 @@ func (s *Server) GetBalanceList(address string) (sdk.Coins, error) {
 		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", address, err.Error())
 		return result, err
 	}
-	req := &banktypes.QueryAllBalancesRequest{Address: targetAddr}
 
-	resp, err := client.AllBalances(context.Background(), req)
+	var nextKey []byte
+	for {
+		req := &banktypes.QueryAllBalancesRequest{
+			Address:    targetAddr,
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.AllBalances(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query balances: %v", err)
+			return sdk.Coins{}, err
+		}
+
+		result = append(result, resp.Balances...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetSpendableBalanceList retrieves the balances of an address that are not locked, e.g. by vesting.
+//
+// @param address the address to query the spendable balances for
+// @return a list of coins representing the spendable balances, or an error if the retrieval fails
+func (s *Server) GetSpendableBalanceList(address string) (sdk.Coins, error) {
+	result := sdk.Coins{}
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return result, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	targetAddr, err := ConvertToCysicAddress(address)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", address, err.Error())
+		return result, err
+	}
+
+	var nextKey []byte
+	for {
+		req := &banktypes.QuerySpendableBalancesRequest{
+			Address:    targetAddr,
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.SpendableBalances(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query spendable balances: %v", err)
+			return sdk.Coins{}, err
+		}
+
+		result = append(result, resp.Balances...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetBalanceByDenom retrieves the balance of a single denomination for an address.
+//
+// @param address the address to query the balance for
+// @param denom the coin denomination to retrieve
+// @return the balance in base units, or an error if the retrieval fails
+func (s *Server) GetBalanceByDenom(address string, denom string) (sdk.Coin, error) {
+	result := sdk.NewCoin(denom, sdkmath.ZeroInt())
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return result, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	targetAddr, err := ConvertToCysicAddress(address)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", address, err.Error())
+		return result, err
+	}
+
+	req := &banktypes.QueryBalanceRequest{Address: targetAddr, Denom: denom}
+	resp, err := client.Balance(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query balance: %v", err)
+		return result, err
+	}
+
+	if resp.Balance != nil {
+		result = *resp.Balance
+	}
+
+	return result, nil
+}
+
+// GetTotalSupply retrieves the total supply of all denominations.
+//
+// @return a list of coins representing the total supply, or an error if the retrieval fails
+func (s *Server) GetTotalSupply() (sdk.Coins, error) {
+	result := sdk.Coins{}
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return result, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	var nextKey []byte
+	for {
+		req := &banktypes.QueryTotalSupplyRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.TotalSupply(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query total supply: %v", err)
+			return sdk.Coins{}, err
+		}
+
+		result = append(result, resp.Supply...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetSupplyOf retrieves the total supply of a single denomination.
+//
+// @param denom the coin denomination to retrieve
+// @return the supply in base units, or an error if the retrieval fails
+func (s *Server) GetSupplyOf(denom string) (sdk.Coin, error) {
+	result := sdk.NewCoin(denom, sdkmath.ZeroInt())
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return result, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	resp, err := client.SupplyOf(s.queryContext(), &banktypes.QuerySupplyOfRequest{Denom: denom})
 	if err != nil {
-		log.Printf("could not query balances: %v", err)
+		log.Printf("could not query supply of %v: %v", denom, err)
 		return result, err
 	}
 
-	return resp.Balances, nil
+	return resp.Amount, nil
+}
+
+// GetDenomOwners retrieves every address holding a denomination together with its balance.
+//
+// @param denom the coin denomination to retrieve the owners of
+// @return a list of owners, or an error if the retrieval fails
+func (s *Server) GetDenomOwners(denom string) ([]*banktypes.DenomOwner, error) {
+	result := make([]*banktypes.DenomOwner, 0)
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return result, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	var nextKey []byte
+	for {
+		req := &banktypes.QueryDenomOwnersRequest{
+			Denom:      denom,
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.DenomOwners(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query denom owners of %v: %v", denom, err)
+			return nil, err
+		}
+
+		result = append(result, resp.DenomOwners...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetBankParams retrieves the parameters of the bank module.
+//
+// @return the bank parameters, or an error if the retrieval fails
+func (s *Server) GetBankParams() (banktypes.Params, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return banktypes.Params{}, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	resp, err := client.Params(s.queryContext(), &banktypes.QueryParamsRequest{})
+	if err != nil {
+		log.Printf("could not query bank params: %v", err)
+		return banktypes.Params{}, err
+	}
+
+	return resp.Params, nil
 }
 
 // Send facilitates the sending of coins from one address to another.
@@ -124,8 +330,24 @@ func (s *Server) MultiSend(signer Signer, toAddrList []string, coin string, amou
 // @param amountList the list of amounts to send for each coin denomination
 // @return the transaction hash as a string, or an error if the send operation fails
 func (s *Server) MultiSendWithDiffAmount(signer Signer, toAddrList []string, coinList []string, amountList []sdkmath.Int) (string, error) {
+	msg, err := buildMultiSendMsg(signer.CosmosAddr, toAddrList, coinList, amountList)
+	if err != nil {
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+}
+
+// buildMultiSendMsg builds a MsgMultiSend paying a different amount of coins to each address from a single sender.
+//
+// @param from the address of the sender
+// @param toAddrList the list of addresses to send coins to
+// @param coinList the list of coin denominations to send
+// @param amountList the list of amounts to send for each coin denomination
+// @return the MsgMultiSend, or an error if the params are invalid
+func buildMultiSendMsg(from sdk.AccAddress, toAddrList []string, coinList []string, amountList []sdkmath.Int) (*banktypes.MsgMultiSend, error) {
 	if len(toAddrList) != len(coinList) || len(coinList) != len(amountList) {
-		return "", fmt.Errorf("params length not equal, len(toAddr): %v, len(coinList): %v, len(amountList): %v",
+		return nil, fmt.Errorf("params length not equal, len(toAddr): %v, len(coinList): %v, len(amountList): %v",
 			len(toAddrList), len(coinList), len(amountList))
 	}
 
@@ -145,24 +367,23 @@ func (s *Server) MultiSendWithDiffAmount(signer Signer, toAddrList []string, coi
 		newCoin := sdk.NewCoin(coin, amount)
 		coins = coins.Add(newCoin)
 	}
-	in := []banktypes.Input{banktypes.NewInput(signer.CosmosAddr, coins)}
+	in := []banktypes.Input{banktypes.NewInput(from, coins)}
 	var out []banktypes.Output
 	for i, toAddr := range toAddrList {
 		toAddrCosmos, err := ConvertToCysicAddress(toAddr)
 		if err != nil {
 			log.Printf("error when convert to addr: %v, err: %v", toAddr, err.Error())
-			return "", err
+			return nil, err
 		}
 		to, err := sdk.AccAddressFromBech32(toAddrCosmos)
 		if err != nil {
 			log.Printf("error when conert addr to accAddr, addr: %v, err: %v", toAddrCosmos, err.Error())
-			return "", err
+			return nil, err
 		}
 
 		sendCoins := sdk.NewCoins(sdk.NewCoin(coinList[i], amountList[i]))
 		out = append(out, banktypes.NewOutput(to, sendCoins))
 	}
 
-	msg := banktypes.NewMsgMultiSend(in, out)
-	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+	return banktypes.NewMsgMultiSend(in, out), nil
 }
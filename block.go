@@ -0,0 +1,94 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+const txsByHeightPageLimit = 100
+
+// GetLatestBlockHeight retrieves the height of the latest block.
+//
+// @return the latest block height, or an error if the query fails
+func (s *Server) GetLatestBlockHeight() (int64, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return 0, err
+	}
+
+	client := tmservice.NewServiceClient(s.Conn)
+	resp, err := client.GetLatestBlock(context.Background(), &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		log.Printf("could not query latest block: %v", err)
+		return 0, err
+	}
+	if resp.Block == nil {
+		return 0, fmt.Errorf("latest block is empty")
+	}
+
+	return resp.Block.Header.Height, nil
+}
+
+// GetBlockByHeight retrieves a block by height.
+//
+// @param height the height of the block
+// @return the block, or an error if the query fails
+func (s *Server) GetBlockByHeight(height int64) (*tmproto.Block, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := tmservice.NewServiceClient(s.Conn)
+	resp, err := client.GetBlockByHeight(context.Background(), &tmservice.GetBlockByHeightRequest{Height: height})
+	if err != nil {
+		log.Printf("could not query block %v: %v", height, err)
+		return nil, err
+	}
+	if resp.Block == nil {
+		return nil, fmt.Errorf("block %v is empty", height)
+	}
+
+	return resp.Block, nil
+}
+
+// GetTxsByHeight retrieves the results of all transactions packed in a block.
+//
+// @param height the height of the block
+// @return the transaction responses, or an error if the query fails
+func (s *Server) GetTxsByHeight(height int64) ([]*sdk.TxResponse, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := sdkTx.NewServiceClient(s.Conn)
+
+	result := make([]*sdk.TxResponse, 0)
+	for page := uint64(1); ; page++ {
+		req := &sdkTx.GetTxsEventRequest{
+			Events:  []string{fmt.Sprintf("tx.height=%d", height)},
+			OrderBy: sdkTx.OrderBy_ORDER_BY_ASC,
+			Page:    page,
+			Limit:   txsByHeightPageLimit,
+		}
+		resp, err := client.GetTxsEvent(context.Background(), req)
+		if err != nil {
+			log.Printf("could not query txs of block %v: %v", height, err)
+			return nil, err
+		}
+
+		result = append(result, resp.TxResponses...)
+		if len(resp.TxResponses) < txsByHeightPageLimit || (resp.Total != 0 && uint64(len(result)) >= resp.Total) {
+			break
+		}
+	}
+
+	return result, nil
+}
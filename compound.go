@@ -0,0 +1,276 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const defaultCompoundInterval = 24 * time.Hour
+
+// CompoundConfig configures a Compounder.
+type CompoundConfig struct {
+	// Interval is the time between two compounding cycles
+	Interval time.Duration
+	// MinReward is the minimum CGT reward, in base units, of a validator to be compounded
+	MinReward math.Int
+	// ReservedGas is the balance of the gas coin, in base units, left untouched for future fees
+	ReservedGas math.Int
+	// GasAdjustment is multiplied with the simulated gas to get the gas limit of a cycle
+	GasAdjustment float64
+	// BeforeBroadcast is called with the planned cycle before it is broadcast, returning an error skips the cycle, optional
+	BeforeBroadcast func(report *CompoundReport) error
+	// AfterCycle is called with the report of every cycle, optional
+	AfterCycle func(report *CompoundReport)
+}
+
+// CompoundValidatorResult is the outcome of a compounding cycle for one validator.
+type CompoundValidatorResult struct {
+	ValidatorAddress string   `json:"validator_address"`
+	Reward           math.Int `json:"reward"`
+	Restaked         math.Int `json:"restaked"`
+	Skipped          bool     `json:"skipped"`
+	Reason           string   `json:"reason,omitempty"`
+}
+
+// CompoundReport is the report of one compounding cycle.
+type CompoundReport struct {
+	Delegator       string                    `json:"delegator"`
+	WithdrawAddress string                    `json:"withdraw_address"`
+	StartTime       time.Time                 `json:"start_time"`
+	EndTime         time.Time                 `json:"end_time"`
+	Validators      []CompoundValidatorResult `json:"validators"`
+	TotalReward     math.Int                  `json:"total_reward"`
+	TotalRestaked   math.Int                  `json:"total_restaked"`
+	GasLimit        uint64                    `json:"gas_limit"`
+	Fee             sdk.Coin                  `json:"fee"`
+	TxHash          string                    `json:"tx_hash,omitempty"`
+	Skipped         bool                      `json:"skipped"`
+	Reason          string                    `json:"reason,omitempty"`
+	Error           string                    `json:"error,omitempty"`
+}
+
+// Compounder periodically withdraws the staking rewards of a signer and delegates the CGT rewards back.
+type Compounder struct {
+	server *Server
+	signer Signer
+	config CompoundConfig
+}
+
+// NewCompounder creates a new Compounder for a signer.
+//
+// @param signer the Signer instance of the delegator
+// @param config the compounding configuration
+// @return a new Compounder instance
+func (s *Server) NewCompounder(signer Signer, config CompoundConfig) *Compounder {
+	if config.Interval <= 0 {
+		config.Interval = defaultCompoundInterval
+	}
+	if config.MinReward.IsNil() {
+		config.MinReward = math.ZeroInt()
+	}
+	if config.ReservedGas.IsNil() {
+		config.ReservedGas = math.ZeroInt()
+	}
+	if config.GasAdjustment <= 0 {
+		config.GasAdjustment = defaultGasAdjustment
+	}
+
+	return &Compounder{
+		server: s,
+		signer: signer,
+		config: config,
+	}
+}
+
+// Run compounds once per interval until ctx is cancelled, a failed cycle is retried at the next interval.
+//
+// @param ctx the context controlling the compounder's lifetime
+// @return the context error once the compounder stops
+func (c *Compounder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.RunOnce(); err != nil {
+			log.Printf("error when compound rewards of %v, err: %v", c.signer.CosmosAddr.String(), err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs one compounding cycle.
+//
+// The rewards of every validator above MinReward are withdrawn and their CGT part delegated back
+// in a single transaction. When the gas coin balance would fall below ReservedGas after paying the
+// fee, the restaked amounts are reduced if the gas coin is CGT, otherwise the cycle is skipped.
+// The cycle is also skipped when the rewards are withdrawn to another address than the delegator,
+// which couldn't restake them.
+//
+// @return the report of the cycle, or an error if a query or the broadcast fails
+func (c *Compounder) RunOnce() (*CompoundReport, error) {
+	report := &CompoundReport{
+		Delegator:     c.signer.CosmosAddr.String(),
+		StartTime:     time.Now(),
+		Validators:    make([]CompoundValidatorResult, 0),
+		TotalReward:   math.ZeroInt(),
+		TotalRestaked: math.ZeroInt(),
+	}
+
+	err := c.runOnce(report)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	report.EndTime = time.Now()
+
+	if c.config.AfterCycle != nil {
+		c.config.AfterCycle(report)
+	}
+
+	return report, err
+}
+
+func (c *Compounder) runOnce(report *CompoundReport) error {
+	s := c.server
+
+	withdrawAddress, err := s.QueryWithdrawAddress(report.Delegator)
+	if err != nil {
+		return err
+	}
+	report.WithdrawAddress = withdrawAddress
+	if withdrawAddress != report.Delegator {
+		report.Skipped = true
+		report.Reason = fmt.Sprintf("rewards are withdrawn to %v, not to the delegator", withdrawAddress)
+		return nil
+	}
+
+	// withdrawals pay out truncated rewards, so the exact rewards are truncated rather than rounded
+	rewardMap, _, err := s.QueryDelegateRewardDec(report.Delegator)
+	if err != nil {
+		return err
+	}
+
+	validatorList := make([]string, 0, len(rewardMap))
+	for validator := range rewardMap {
+		validatorList = append(validatorList, validator)
+	}
+	sort.Strings(validatorList)
+
+	selected := make([]int, 0)
+	for _, validator := range validatorList {
+		result := CompoundValidatorResult{
+			ValidatorAddress: validator,
+			Reward:           rewardMap[validator].AmountOf(CGTToken).TruncateInt(),
+			Restaked:         math.ZeroInt(),
+		}
+		report.TotalReward = report.TotalReward.Add(result.Reward)
+
+		if !result.Reward.IsPositive() || result.Reward.LT(c.config.MinReward) {
+			result.Skipped = true
+			result.Reason = fmt.Sprintf("reward below minimum %v", c.config.MinReward)
+		} else {
+			result.Restaked = result.Reward
+			selected = append(selected, len(report.Validators))
+		}
+		report.Validators = append(report.Validators, result)
+	}
+
+	if len(selected) == 0 {
+		report.Skipped = true
+		report.Reason = "no validator above minimum reward"
+		return nil
+	}
+
+	gasUsed, err := s.SimulateTx(c.signer, c.buildMsgList(report, selected))
+	if err != nil {
+		return err
+	}
+	report.GasLimit = uint64(float64(gasUsed) * c.config.GasAdjustment)
+	report.Fee = sdk.NewCoin(s.GasCoin, sdk.NewDec(s.GasPrice*int64(report.GasLimit)).Ceil().RoundInt())
+
+	balance, err := s.GetBalanceByDenom(report.Delegator, s.GasCoin)
+	if err != nil {
+		return err
+	}
+	shortfall := c.config.ReservedGas.Add(report.Fee.Amount).Sub(balance.Amount)
+	if shortfall.IsPositive() {
+		if s.GasCoin != CGTToken {
+			report.Skipped = true
+			report.Reason = fmt.Sprintf("%v balance %v can't cover fee %v and reserved %v", s.GasCoin, balance.Amount, report.Fee.Amount, c.config.ReservedGas)
+			return nil
+		}
+
+		// keep part of the withdrawn CGT to cover the shortfall
+		for i := len(selected) - 1; i >= 0 && shortfall.IsPositive(); i-- {
+			result := &report.Validators[selected[i]]
+			kept := math.MinInt(result.Restaked, shortfall)
+			result.Restaked = result.Restaked.Sub(kept)
+			shortfall = shortfall.Sub(kept)
+		}
+		if shortfall.IsPositive() {
+			report.Skipped = true
+			report.Reason = fmt.Sprintf("rewards can't cover fee %v and reserved %v", report.Fee.Amount, c.config.ReservedGas)
+			return nil
+		}
+	}
+
+	for _, index := range selected {
+		report.TotalRestaked = report.TotalRestaked.Add(report.Validators[index].Restaked)
+	}
+
+	if c.config.BeforeBroadcast != nil {
+		if err := c.config.BeforeBroadcast(report); err != nil {
+			report.Skipped = true
+			report.Reason = err.Error()
+			return nil
+		}
+	}
+
+	txHash, err := s.buildAndBroadcastCosmosTxWithGas(c.signer, c.buildMsgList(report, selected), report.GasLimit)
+	if err != nil {
+		return err
+	}
+	report.TxHash = txHash
+
+	return nil
+}
+
+// buildMsgList builds the withdraw and delegate messages of the selected validators.
+func (c *Compounder) buildMsgList(report *CompoundReport, selected []int) []sdk.Msg {
+	msgList := make([]sdk.Msg, 0, len(selected)*2)
+	for _, index := range selected {
+		result := report.Validators[index]
+		msgList = append(msgList, &distributiontypes.MsgWithdrawDelegatorReward{
+			DelegatorAddress: report.Delegator,
+			ValidatorAddress: result.ValidatorAddress,
+		})
+	}
+	for _, index := range selected {
+		result := report.Validators[index]
+		if !result.Restaked.IsPositive() {
+			continue
+		}
+		msgList = append(msgList, &stakingtypes.MsgDelegate{
+			DelegatorAddress: report.Delegator,
+			ValidatorAddress: result.ValidatorAddress,
+			Amount: sdk.Coin{
+				Denom:  CGTToken,
+				Amount: result.Restaked,
+			},
+		})
+	}
+
+	return msgList
+}
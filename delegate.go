@@ -1,7 +1,8 @@
 package gosdk
 
 import (
-	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 
 	delegatetypes "github.com/hack2fun/gosdk/types/delegate"
@@ -35,7 +36,7 @@ func (s *Server) QueryDelegatorDelegations(delegatorAddress string) (map[string]
 		DelegatorAddr: targetAddr,
 	}
 
-	resp, err := client.DelegatorDelegations(context.Background(), req)
+	resp, err := client.DelegatorDelegations(s.queryContext(), req)
 	if err != nil {
 		log.Printf("could not query delegateReward: %v", err)
 		return result, err
@@ -79,6 +80,8 @@ func (s *Server) QueryDelegatorDelegations(delegatorAddress string) (map[string]
 
 // QueryDelegateReward retrieves the total rewards for a delegator across all validators.
 //
+// Rewards are rounded to integers, use QueryDelegateRewardDec for the exact amounts.
+//
 // @param delegatorAddress the address of the delegator
 // @return a map where each key is a validator address and the value is a list of coins representing the total rewards earned by the delegator from that validator, or an error if the query fails
 func (s *Server) QueryDelegateReward(delegatorAddress string) (map[string][]sdk.Coin, error) {
@@ -100,7 +103,7 @@ func (s *Server) QueryDelegateReward(delegatorAddress string) (map[string][]sdk.
 		DelegatorAddress: targetAddr,
 	}
 
-	resp, err := client.DelegationTotalRewards(context.Background(), req)
+	resp, err := client.DelegationTotalRewards(s.queryContext(), req)
 	if err != nil {
 		log.Printf("could not query delegateReward: %v", err)
 		return result, err
@@ -250,3 +253,198 @@ func (s *Server) UnDelegateCGT(signer Signer, validatorAddress string, amount ma
 
 	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
 }
+
+// EpochConfig describes how the delegate module splits blocks into epochs.
+//
+// The delegate module has no epoch query, so epochs are derived from block heights: epoch n
+// starts at StartHeight + n*EpochLength.
+type EpochConfig struct {
+	// StartHeight is the first height of epoch 0
+	StartHeight int64
+	// EpochLength is the number of blocks per epoch
+	EpochLength int64
+}
+
+// DelegateBind is the validator a worker's veToken is bound to in an epoch.
+type DelegateBind struct {
+	Epoch     int64  `json:"epoch"`
+	Worker    string `json:"worker"`
+	Token     string `json:"token"`
+	Validator string `json:"validator"`
+	Amount    string `json:"amount"`
+	// Changed reports whether the bind differs from the previous epoch of a history walk
+	Changed bool `json:"changed"`
+}
+
+// DelegateCValue is the c-value of a validator in an epoch.
+type DelegateCValue struct {
+	Epoch     int64  `json:"epoch"`
+	Validator string `json:"validator"`
+	// CValue is the raw value returned by the chain
+	CValue string `json:"c_value"`
+	// Workers is the per-worker breakdown, when CValue is a JSON list of worker c-values
+	Workers []delegatetypes.MsgCValue `json:"workers,omitempty"`
+	// Changed reports whether the c-value differs from the previous epoch of a history walk
+	Changed bool `json:"changed"`
+}
+
+// EpochAtHeight returns the epoch a block height belongs to.
+//
+// @param height the block height
+// @return the epoch, or an error if the config is invalid or the height is before StartHeight
+func (c EpochConfig) EpochAtHeight(height int64) (int64, error) {
+	if c.EpochLength <= 0 {
+		return 0, fmt.Errorf("epoch length must be positive, got: %v", c.EpochLength)
+	}
+	if height < c.StartHeight {
+		return 0, fmt.Errorf("height %v is before the first epoch at %v", height, c.StartHeight)
+	}
+
+	return (height - c.StartHeight) / c.EpochLength, nil
+}
+
+// EpochStartHeight returns the first block height of an epoch.
+//
+// @param epoch the epoch
+// @return the first height of the epoch
+func (c EpochConfig) EpochStartHeight(epoch int64) int64 {
+	return c.StartHeight + epoch*c.EpochLength
+}
+
+// GetCurrentEpoch resolves the epoch of the latest block.
+//
+// @param config the epoch config of the chain
+// @return the current epoch, or an error if the latest height can't be queried
+func (s *Server) GetCurrentEpoch(config EpochConfig) (int64, error) {
+	height, err := s.GetLatestBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	return config.EpochAtHeight(height)
+}
+
+// QueryDelegateBind retrieves the validator a worker's veToken is bound to in an epoch.
+//
+// @param epoch the epoch
+// @param worker the 0x or cysic address of the worker
+// @param token the veToken
+// @return the bind, or an error if the query fails
+func (s *Server) QueryDelegateBind(epoch int64, worker string, token string) (*DelegateBind, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	workerAddr, err := ConvertToETHAddress(worker)
+	if err != nil {
+		log.Printf("error when convert addr: %v to ethAddr, err: %v", worker, err.Error())
+		return nil, err
+	}
+
+	client := delegatetypes.NewQueryClient(s.Conn)
+	req := &delegatetypes.QueryDelegateBindRequest{
+		Epoch:  epoch,
+		Worker: workerAddr,
+		Token:  token,
+	}
+	resp, err := client.QueryDelegateBind(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query delegate bind: %v", err)
+		return nil, err
+	}
+
+	return &DelegateBind{
+		Epoch:     epoch,
+		Worker:    workerAddr,
+		Token:     token,
+		Validator: resp.Validator,
+		Amount:    resp.Amount,
+	}, nil
+}
+
+// QueryDelegateCValue retrieves the c-value of a validator in an epoch.
+//
+// @param epoch the epoch
+// @param validator the address of the validator
+// @return the c-value, or an error if the query fails
+func (s *Server) QueryDelegateCValue(epoch int64, validator string) (*DelegateCValue, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := delegatetypes.NewQueryClient(s.Conn)
+	req := &delegatetypes.QueryDelegateCValueRequest{
+		Epoch:     epoch,
+		Validator: validator,
+	}
+	resp, err := client.QueryDelegateCValue(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query delegate c-value: %v", err)
+		return nil, err
+	}
+
+	result := &DelegateCValue{
+		Epoch:     epoch,
+		Validator: validator,
+		CValue:    resp.CValue,
+	}
+	var workers []delegatetypes.MsgCValue
+	if err := json.Unmarshal([]byte(resp.CValue), &workers); err == nil {
+		result.Workers = workers
+	}
+
+	return result, nil
+}
+
+// IterateDelegateBindHistory calls fn with the bind of a worker's veToken for every epoch from fromEpoch to toEpoch.
+//
+// @param worker the 0x or cysic address of the worker
+// @param token the veToken
+// @param fromEpoch the first epoch
+// @param toEpoch the last epoch
+// @param fn the function called for each epoch, returning an error stops the iteration
+// @return the error returned by fn, or an error if a query fails
+func (s *Server) IterateDelegateBindHistory(worker string, token string, fromEpoch int64, toEpoch int64, fn func(bind *DelegateBind) error) error {
+	var previous *DelegateBind
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		bind, err := s.QueryDelegateBind(epoch, worker, token)
+		if err != nil {
+			return err
+		}
+		bind.Changed = previous == nil || previous.Validator != bind.Validator || previous.Amount != bind.Amount
+
+		if err := fn(bind); err != nil {
+			return err
+		}
+		previous = bind
+	}
+
+	return nil
+}
+
+// IterateDelegateCValueHistory calls fn with the c-value of a validator for every epoch from fromEpoch to toEpoch.
+//
+// @param validator the address of the validator
+// @param fromEpoch the first epoch
+// @param toEpoch the last epoch
+// @param fn the function called for each epoch, returning an error stops the iteration
+// @return the error returned by fn, or an error if a query fails
+func (s *Server) IterateDelegateCValueHistory(validator string, fromEpoch int64, toEpoch int64, fn func(cValue *DelegateCValue) error) error {
+	var previous *DelegateCValue
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		cValue, err := s.QueryDelegateCValue(epoch, validator)
+		if err != nil {
+			return err
+		}
+		cValue.Changed = previous == nil || previous.CValue != cValue.CValue
+
+		if err := fn(cValue); err != nil {
+			return err
+		}
+		previous = cValue
+	}
+
+	return nil
+}
@@ -94,7 +94,11 @@ func printBalance(addr string) {
 	fmt.Print("addr: ", addr, "balance: ")
 	balanceList, _ := defaultServer.GetBalanceList(addr)
 	for i, coin := range balanceList {
-		fmt.Printf("%v%v", decimal.NewFromBigInt(coin.Amount.BigInt(), -18), coin.Denom)
+		display, err := defaultServer.FormatCoin(coin)
+		if err != nil {
+			display = coin.String()
+		}
+		fmt.Print(display)
 		if i != len(balanceList)-1 {
 			fmt.Print(", ")
 		}
@@ -0,0 +1,289 @@
+package gosdk
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var displayAmountRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z][a-zA-Z0-9/:._-]{2,127})$`)
+
+// DenomUnit describes how a display amount of a denomination maps to its base units.
+type DenomUnit struct {
+	Base     string
+	Display  string
+	Exponent uint32
+}
+
+// GetDenomMetadata retrieves the bank metadata of a denomination.
+//
+// @param denom the base denomination
+// @return the metadata, or an error if the query fails
+func (s *Server) GetDenomMetadata(denom string) (*banktypes.Metadata, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+	resp, err := client.DenomMetadata(s.queryContext(), &banktypes.QueryDenomMetadataRequest{Denom: denom})
+	if err != nil {
+		log.Printf("could not query denom metadata: %v", err)
+		return nil, err
+	}
+
+	return &resp.Metadata, nil
+}
+
+// GetDenomsMetadata retrieves the bank metadata of all denominations.
+//
+// @return the list of metadata, or an error if the query fails
+func (s *Server) GetDenomsMetadata() ([]banktypes.Metadata, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := banktypes.NewQueryClient(s.Conn)
+
+	result := make([]banktypes.Metadata, 0)
+	var nextKey []byte
+	for {
+		req := &banktypes.QueryDenomsMetadataRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.DenomsMetadata(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query denoms metadata: %v", err)
+			return nil, err
+		}
+
+		result = append(result, resp.Metadatas...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetDenomUnit resolves the base denomination and exponent of the unit a denomination names.
+//
+// The denom can be either a base denomination, which has exponent 0, or one of its metadata units. When
+// the chain has no metadata for it, e.g. for CGT and CYS, the denom is treated as a base denomination with
+// BaseDenomUnit decimals.
+//
+// @param denom the denomination to resolve
+// @return the resolved unit, or an error if the query fails
+func (s *Server) GetDenomUnit(denom string) (DenomUnit, error) {
+	metadata, err := s.GetDenomMetadata(denom)
+	if err == nil {
+		return DenomUnit{Base: metadata.Base, Display: metadata.Base, Exponent: 0}, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return DenomUnit{}, err
+	}
+
+	return s.namedDenomUnit(denom)
+}
+
+// namedDenomUnit looks up a denomination that is not a base denomination among the metadata units.
+func (s *Server) namedDenomUnit(denom string) (DenomUnit, error) {
+	metadataList, err := s.GetDenomsMetadata()
+	if err != nil {
+		return DenomUnit{}, err
+	}
+	for _, metadata := range metadataList {
+		for _, unit := range metadata.DenomUnits {
+			if unit.Denom == denom || containsString(unit.Aliases, denom) {
+				return DenomUnit{Base: metadata.Base, Display: unit.Denom, Exponent: unit.Exponent}, nil
+			}
+		}
+	}
+
+	return DenomUnit{Base: denom, Display: denom, Exponent: cysicTypes.BaseDenomUnit}, nil
+}
+
+// displayUnit resolves the unit amounts of a denomination are shown in: the display unit of its metadata
+// when denom is a base denomination, otherwise the unit denom names.
+func (s *Server) displayUnit(denom string) (DenomUnit, error) {
+	metadata, err := s.GetDenomMetadata(denom)
+	if err == nil {
+		if unit, ok := displayUnitOf(*metadata); ok {
+			return unit, nil
+		}
+		return DenomUnit{Base: metadata.Base, Display: metadata.Base, Exponent: 0}, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return DenomUnit{}, err
+	}
+
+	return s.namedDenomUnit(denom)
+}
+
+// ParseDisplayAmount parses a human readable amount such as "1.5CYS" into base units.
+//
+// @param amount the amount with its denomination
+// @return the amount in base units, or an error if it is malformed or too precise for the denomination
+func (s *Server) ParseDisplayAmount(amount string) (sdk.Coin, error) {
+	matches := displayAmountRegex.FindStringSubmatch(strings.TrimSpace(amount))
+	if matches == nil {
+		return sdk.Coin{}, fmt.Errorf("invalid amount: %v", amount)
+	}
+
+	value, err := decimal.NewFromString(matches[1])
+	if err != nil {
+		return sdk.Coin{}, fmt.Errorf("invalid amount: %v, err: %v", amount, err)
+	}
+
+	unit, err := s.GetDenomUnit(matches[2])
+	if err != nil {
+		log.Printf("error when get denom unit: %v, err: %v", matches[2], err.Error())
+		return sdk.Coin{}, err
+	}
+
+	baseAmount, err := ToBaseUnits(value, unit.Exponent)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.NewCoin(unit.Base, baseAmount), nil
+}
+
+// FormatCoin formats a base unit coin as a human readable amount such as "1.5CYS".
+//
+// @param coin the coin in base units
+// @return the formatted amount, or an error if the denomination cannot be resolved
+func (s *Server) FormatCoin(coin sdk.Coin) (string, error) {
+	unit, err := s.displayUnit(coin.Denom)
+	if err != nil {
+		log.Printf("error when get denom unit: %v, err: %v", coin.Denom, err.Error())
+		return "", err
+	}
+
+	return FromBaseUnits(coin.Amount, unit.Exponent).String() + unit.Display, nil
+}
+
+// ToBaseUnits converts a display amount to base units.
+//
+// @param amount the display amount
+// @param exponent the number of decimals of the denomination
+// @return the amount in base units, or an error if it is negative or has more decimals than exponent
+func ToBaseUnits(amount decimal.Decimal, exponent uint32) (sdkmath.Int, error) {
+	if amount.IsNegative() {
+		return sdkmath.Int{}, fmt.Errorf("amount can't be negative: %v", amount)
+	}
+
+	shifted := amount.Shift(int32(exponent))
+	if !shifted.Equal(shifted.Truncate(0)) {
+		return sdkmath.Int{}, fmt.Errorf("amount %v exceeds the precision of %v decimals", amount, exponent)
+	}
+
+	return sdkmath.NewIntFromBigInt(shifted.BigInt()), nil
+}
+
+// FromBaseUnits converts an amount in base units to a display amount.
+//
+// @param amount the amount in base units
+// @param exponent the number of decimals of the denomination
+// @return the display amount
+func FromBaseUnits(amount sdkmath.Int, exponent uint32) decimal.Decimal {
+	return decimal.NewFromBigInt(amount.BigInt(), -int32(exponent))
+}
+
+// SendDecimal sends a display amount of coins, e.g. 1.5 CYS, to another address.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param toAddrStr the address to send coins to
+// @param coin the coin denomination to send
+// @param amount the display amount to send
+// @return the transaction hash as a string, or an error if the amount is invalid or the send operation fails
+func (s *Server) SendDecimal(signer Signer, toAddrStr string, coin string, amount decimal.Decimal) (string, error) {
+	baseCoin, err := s.toBaseCoin(coin, amount)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Send(signer, toAddrStr, baseCoin.Denom, baseCoin.Amount)
+}
+
+// DelegateCGTDecimal delegates a display amount of CGT tokens to a validator.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param validatorAddress the address of the validator
+// @param amount the display amount to delegate
+// @return the transaction hash as a string, or an error if the amount is invalid or the delegation fails
+func (s *Server) DelegateCGTDecimal(signer Signer, validatorAddress string, amount decimal.Decimal) (string, error) {
+	baseCoin, err := s.toBaseCoin(CGTToken, amount)
+	if err != nil {
+		return "", err
+	}
+
+	return s.DelegateCGT(signer, validatorAddress, baseCoin.Amount)
+}
+
+// UnDelegateCGTDecimal undelegates a display amount of CGT tokens from a validator.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param validatorAddress the address of the validator
+// @param amount the display amount to undelegate
+// @return the transaction hash as a string, or an error if the amount is invalid or the undelegation fails
+func (s *Server) UnDelegateCGTDecimal(signer Signer, validatorAddress string, amount decimal.Decimal) (string, error) {
+	baseCoin, err := s.toBaseCoin(CGTToken, amount)
+	if err != nil {
+		return "", err
+	}
+
+	return s.UnDelegateCGT(signer, validatorAddress, baseCoin.Amount)
+}
+
+func (s *Server) toBaseCoin(denom string, amount decimal.Decimal) (sdk.Coin, error) {
+	if !amount.IsPositive() {
+		return sdk.Coin{}, fmt.Errorf("amount must be positive: %v", amount)
+	}
+
+	unit, err := s.displayUnit(denom)
+	if err != nil {
+		log.Printf("error when get denom unit: %v, err: %v", denom, err.Error())
+		return sdk.Coin{}, err
+	}
+
+	baseAmount, err := ToBaseUnits(amount, unit.Exponent)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.NewCoin(unit.Base, baseAmount), nil
+}
+
+func displayUnitOf(metadata banktypes.Metadata) (DenomUnit, bool) {
+	for _, unit := range metadata.DenomUnits {
+		if unit.Denom == metadata.Display {
+			return DenomUnit{Base: metadata.Base, Display: unit.Denom, Exponent: unit.Exponent}, true
+		}
+	}
+
+	return DenomUnit{}, false
+}
+
+func containsString(list []string, target string) bool {
+	for _, item := range list {
+		if item == target {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+package gosdk
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeBankQuery serves the denom metadata queries of the bank module.
+type fakeBankQuery struct {
+	banktypes.UnimplementedQueryServer
+	metadataList []banktypes.Metadata
+}
+
+func (q *fakeBankQuery) DenomMetadata(_ context.Context, req *banktypes.QueryDenomMetadataRequest) (*banktypes.QueryDenomMetadataResponse, error) {
+	for _, metadata := range q.metadataList {
+		if metadata.Base == req.Denom {
+			return &banktypes.QueryDenomMetadataResponse{Metadata: metadata}, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "client metadata for denom %s", req.Denom)
+}
+
+func (q *fakeBankQuery) DenomsMetadata(context.Context, *banktypes.QueryDenomsMetadataRequest) (*banktypes.QueryDenomsMetadataResponse, error) {
+	return &banktypes.QueryDenomsMetadataResponse{Metadatas: q.metadataList}, nil
+}
+
+// newFakeBankServer starts an in-memory gRPC server with the bank queries and returns a Server connected to it.
+func newFakeBankServer(t *testing.T, metadataList []banktypes.Metadata) *Server {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	banktypes.RegisterQueryServer(grpcServer, &fakeBankQuery{metadataList: metadataList})
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &Server{EndPoint: "bufnet", Conn: conn}
+}
+
+func TestParseDisplayAmount(t *testing.T) {
+	server := newFakeBankServer(t, []banktypes.Metadata{
+		{
+			Base:    "ucys",
+			Display: "CYS",
+			DenomUnits: []*banktypes.DenomUnit{
+				{Denom: "ucys", Exponent: 0},
+				{Denom: "mcys", Exponent: 3, Aliases: []string{"millicys"}},
+				{Denom: "CYS", Exponent: 6},
+			},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		amount  string
+		want    sdk.Coin
+		wantErr bool
+	}{
+		{name: "display unit", amount: "1.5CYS", want: sdk.NewCoin("ucys", math.NewInt(1500000))},
+		{name: "space and padding", amount: " 2 CYS ", want: sdk.NewCoin("ucys", math.NewInt(2000000))},
+		{name: "base denom", amount: "42ucys", want: sdk.NewCoin("ucys", math.NewInt(42))},
+		{name: "fraction of base denom", amount: "0.5ucys", wantErr: true},
+		{name: "intermediate unit", amount: "1.5mcys", want: sdk.NewCoin("ucys", math.NewInt(1500))},
+		{name: "alias", amount: "2millicys", want: sdk.NewCoin("ucys", math.NewInt(2000))},
+		{name: "no metadata uses base denom decimals", amount: "1.5CGT", want: sdk.NewCoin("CGT", math.NewInt(1500000000000000000))},
+		{name: "too precise", amount: "0.0000001CYS", wantErr: true},
+		{name: "fraction of base unit", amount: "0.5mcys", want: sdk.NewCoin("ucys", math.NewInt(500))},
+		{name: "below base unit", amount: "0.0005mcys", wantErr: true},
+		{name: "negative", amount: "-1CYS", wantErr: true},
+		{name: "missing denom", amount: "1.5", wantErr: true},
+		{name: "missing amount", amount: "CYS", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := server.ParseDisplayAmount(tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDisplayAmount(%q) err = %v, wantErr %v", tt.amount, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.IsEqual(tt.want) {
+				t.Errorf("ParseDisplayAmount(%q) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCoin(t *testing.T) {
+	server := newFakeBankServer(t, []banktypes.Metadata{
+		{
+			Base:    "ucys",
+			Display: "CYS",
+			DenomUnits: []*banktypes.DenomUnit{
+				{Denom: "ucys", Exponent: 0},
+				{Denom: "CYS", Exponent: 6},
+			},
+		},
+	})
+
+	tests := []struct {
+		name string
+		coin sdk.Coin
+		want string
+	}{
+		{name: "base denom in display units", coin: sdk.NewCoin("ucys", math.NewInt(1500000)), want: "1.5CYS"},
+		{name: "no metadata uses base denom decimals", coin: sdk.NewCoin("CGT", math.NewInt(2000000000000000000)), want: "2CGT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := server.FormatCoin(tt.coin)
+			if err != nil {
+				t.Fatalf("FormatCoin(%v) err = %v", tt.coin, err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatCoin(%v) = %v, want %v", tt.coin, got, tt.want)
+			}
+		})
+	}
+}
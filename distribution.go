@@ -0,0 +1,309 @@
+package gosdk
+
+import (
+	"fmt"
+	"log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+)
+
+// WithdrawAllDelegatorRewards withdraws the rewards of a delegator from every validator it delegates to in one transaction.
+//
+// @param signer the Signer instance used to sign the transaction
+// @return the transaction hash as a string, or an error if the delegator has no validator or the withdrawal fails
+func (s *Server) WithdrawAllDelegatorRewards(signer Signer) (string, error) {
+	delegatorAddr := signer.CosmosAddr.String()
+
+	validatorList, err := s.QueryDelegatorValidators(delegatorAddr)
+	if err != nil {
+		return "", err
+	}
+	if len(validatorList) == 0 {
+		return "", fmt.Errorf("delegator %v has no validator to withdraw rewards from", delegatorAddr)
+	}
+
+	msgList := make([]sdk.Msg, 0, len(validatorList))
+	for _, validatorAddress := range validatorList {
+		msgList = append(msgList, &distributiontypes.MsgWithdrawDelegatorReward{
+			DelegatorAddress: delegatorAddr,
+			ValidatorAddress: validatorAddress,
+		})
+	}
+
+	return s.buildAndBroadcastSimulatedCosmosTx(signer, msgList)
+}
+
+// SetWithdrawAddress sets the address rewards and commission of the signer are withdrawn to.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param withdrawAddress the 0x or cysic address to withdraw to
+// @return the transaction hash as a string, or an error if the operation fails
+func (s *Server) SetWithdrawAddress(signer Signer, withdrawAddress string) (string, error) {
+	withdrawAddr, err := ConvertToCysicAddress(withdrawAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", withdrawAddress, err.Error())
+		return "", err
+	}
+
+	msg := &distributiontypes.MsgSetWithdrawAddress{
+		DelegatorAddress: signer.CosmosAddr.String(),
+		WithdrawAddress:  withdrawAddr,
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+}
+
+// WithdrawValidatorCommission withdraws the commission of the validator operated by the signer.
+//
+// @param signer the Signer instance of the validator operator
+// @return the transaction hash as a string, or an error if the withdrawal fails
+func (s *Server) WithdrawValidatorCommission(signer Signer) (string, error) {
+	msg := &distributiontypes.MsgWithdrawValidatorCommission{
+		ValidatorAddress: sdk.ValAddress(signer.CosmosAddr).String(),
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+}
+
+// FundCommunityPool sends coins from the signer to the community pool.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param amount the coins to send
+// @return the transaction hash as a string, or an error if the operation fails
+func (s *Server) FundCommunityPool(signer Signer, amount sdk.Coins) (string, error) {
+	msg := distributiontypes.NewMsgFundCommunityPool(amount, signer.CosmosAddr)
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+}
+
+// QueryDelegatorValidators retrieves the validators a delegator delegates to.
+//
+// @param delegatorAddress the address of the delegator
+// @return the validator addresses, or an error if the query fails
+func (s *Server) QueryDelegatorValidators(delegatorAddress string) ([]string, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		return nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	req := &distributiontypes.QueryDelegatorValidatorsRequest{DelegatorAddress: targetAddr}
+	resp, err := client.DelegatorValidators(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query delegator validators: %v", err)
+		return nil, err
+	}
+
+	return resp.Validators, nil
+}
+
+// QueryDelegateRewardDec retrieves the rewards of a delegator across all validators as exact decimals.
+//
+// @param delegatorAddress the address of the delegator
+// @return a map where each key is a validator address and the value is the unrounded rewards from that validator, and the total rewards, or an error if the query fails
+func (s *Server) QueryDelegateRewardDec(delegatorAddress string) (map[string]sdk.DecCoins, sdk.DecCoins, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, nil, err
+	}
+
+	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		return nil, nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	req := &distributiontypes.QueryDelegationTotalRewardsRequest{DelegatorAddress: targetAddr}
+	resp, err := client.DelegationTotalRewards(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query delegateReward: %v", err)
+		return nil, nil, err
+	}
+
+	result := make(map[string]sdk.DecCoins)
+	for _, reward := range resp.Rewards {
+		result[reward.ValidatorAddress] = result[reward.ValidatorAddress].Add(reward.Reward...)
+	}
+
+	return result, resp.Total, nil
+}
+
+// QueryWithdrawAddress retrieves the address rewards of a delegator are withdrawn to.
+//
+// @param delegatorAddress the address of the delegator
+// @return the withdraw address, or an error if the query fails
+func (s *Server) QueryWithdrawAddress(delegatorAddress string) (string, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		return "", err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	req := &distributiontypes.QueryDelegatorWithdrawAddressRequest{DelegatorAddress: targetAddr}
+	resp, err := client.DelegatorWithdrawAddress(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query withdraw address: %v", err)
+		return "", err
+	}
+
+	return resp.WithdrawAddress, nil
+}
+
+// QueryValidatorOutstandingRewards retrieves the rewards of a validator not yet withdrawn by its delegators and operator.
+//
+// @param validatorAddress the address of the validator
+// @return the outstanding rewards, or an error if the query fails
+func (s *Server) QueryValidatorOutstandingRewards(validatorAddress string) (sdk.DecCoins, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	req := &distributiontypes.QueryValidatorOutstandingRewardsRequest{ValidatorAddress: validatorAddress}
+	resp, err := client.ValidatorOutstandingRewards(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query validator outstanding rewards: %v", err)
+		return nil, err
+	}
+
+	return resp.Rewards.Rewards, nil
+}
+
+// QueryValidatorCommission retrieves the accumulated commission of a validator.
+//
+// @param validatorAddress the address of the validator
+// @return the commission, or an error if the query fails
+func (s *Server) QueryValidatorCommission(validatorAddress string) (sdk.DecCoins, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	req := &distributiontypes.QueryValidatorCommissionRequest{ValidatorAddress: validatorAddress}
+	resp, err := client.ValidatorCommission(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query validator commission: %v", err)
+		return nil, err
+	}
+
+	return resp.Commission.Commission, nil
+}
+
+// QueryValidatorSlashes retrieves the slash events of a validator between two heights.
+//
+// @param validatorAddress the address of the validator
+// @param startingHeight the first height to include
+// @param endingHeight the last height to include
+// @return the slash events, or an error if the query fails
+func (s *Server) QueryValidatorSlashes(validatorAddress string, startingHeight uint64, endingHeight uint64) ([]distributiontypes.ValidatorSlashEvent, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+
+	result := make([]distributiontypes.ValidatorSlashEvent, 0)
+	var nextKey []byte
+	for {
+		req := &distributiontypes.QueryValidatorSlashesRequest{
+			ValidatorAddress: validatorAddress,
+			StartingHeight:   startingHeight,
+			EndingHeight:     endingHeight,
+			Pagination:       &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.ValidatorSlashes(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query validator slashes: %v", err)
+			return nil, err
+		}
+
+		result = append(result, resp.Slashes...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// QueryCommunityPool retrieves the coins held by the community pool.
+//
+// @return the community pool, or an error if the query fails
+func (s *Server) QueryCommunityPool() (sdk.DecCoins, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := distributiontypes.NewQueryClient(s.Conn)
+	resp, err := client.CommunityPool(s.queryContext(), &distributiontypes.QueryCommunityPoolRequest{})
+	if err != nil {
+		log.Printf("could not query community pool: %v", err)
+		return nil, err
+	}
+
+	return resp.Pool, nil
+}
+
+// buildAndBroadcastSimulatedCosmosTx broadcasts a transaction whose gas limit is the simulated gas times
+// defaultGasAdjustment, so the fee follows the gas the messages need instead of the configured gas limit.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param msgList list of messages to include in the transaction
+// @return the transaction hash as a string, or an error if the transaction fails
+func (s *Server) buildAndBroadcastSimulatedCosmosTx(signer Signer, msgList []sdk.Msg) (string, error) {
+	gasUsed, err := s.SimulateTx(signer, msgList)
+	if err != nil {
+		return "", err
+	}
+
+	gasLimit := uint64(float64(gasUsed) * defaultGasAdjustment)
+
+	return s.buildAndBroadcastCosmosTxWithGas(signer, msgList, gasLimit)
+}
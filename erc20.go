@@ -0,0 +1,411 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+)
+
+// erc20ABI is the standard ERC-20 interface.
+const erc20ABI = `[
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"transferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Approval","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+var parsedERC20ABI = mustParseABI(erc20ABI)
+
+// ERC20Transfer is a Transfer event of an ERC-20 token.
+type ERC20Transfer struct {
+	TxHash      string        `json:"tx_hash"`
+	BlockNumber uint64        `json:"block_number"`
+	LogIndex    uint          `json:"log_index"`
+	Token       string        `json:"token"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Amount      sdkmath.Int   `json:"amount"`
+	Removed     bool          `json:"removed"`
+	Log         *ethtypes.Log `json:"-"`
+}
+
+// ERC20 is an ERC-20 token on the EVM side of the chain.
+//
+// Addresses may be given as 0x or cysic addresses. Amounts are in base units as sdkmath.Int, like bank
+// coins, or in display units as decimals scaled by the token decimals, like GetBalance.
+type ERC20 struct {
+	client   *EVMClient
+	Address  common.Address
+	contract *bind.BoundContract
+	decimals *uint8
+}
+
+// NewERC20 binds an ERC-20 token contract.
+//
+// @param tokenAddr the 0x or cysic address of the token contract
+// @return a new ERC20 instance, or an error if the address is invalid
+func (c *EVMClient) NewERC20(tokenAddr string) (*ERC20, error) {
+	address, err := toEthAddress(tokenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ERC20{
+		client:   c,
+		Address:  address,
+		contract: bind.NewBoundContract(address, parsedERC20ABI, c.Backend, c.Backend, c.Backend),
+	}, nil
+}
+
+func (t *ERC20) call(method string, args ...interface{}) (interface{}, error) {
+	var out []interface{}
+	if err := t.contract.Call(&bind.CallOpts{Context: context.Background()}, &out, method, args...); err != nil {
+		log.Printf("could not call %v of erc20 %v: %v", method, t.Address.Hex(), err)
+		return nil, err
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("%v of erc20 %v returned %v values", method, t.Address.Hex(), len(out))
+	}
+
+	return out[0], nil
+}
+
+func (t *ERC20) callAmount(method string, args ...interface{}) (sdkmath.Int, error) {
+	out, err := t.call(method, args...)
+	if err != nil {
+		return sdkmath.Int{}, err
+	}
+
+	return sdkmath.NewIntFromBigInt(*abi.ConvertType(out, new(*big.Int)).(**big.Int)), nil
+}
+
+// Name retrieves the name of the token.
+//
+// @return the name, or an error if the call fails
+func (t *ERC20) Name() (string, error) {
+	out, err := t.call("name")
+	if err != nil {
+		return "", err
+	}
+
+	return *abi.ConvertType(out, new(string)).(*string), nil
+}
+
+// Symbol retrieves the symbol of the token.
+//
+// @return the symbol, or an error if the call fails
+func (t *ERC20) Symbol() (string, error) {
+	out, err := t.call("symbol")
+	if err != nil {
+		return "", err
+	}
+
+	return *abi.ConvertType(out, new(string)).(*string), nil
+}
+
+// Decimals retrieves the number of decimals of the token, the result is cached.
+//
+// @return the decimals, or an error if the call fails
+func (t *ERC20) Decimals() (uint8, error) {
+	if t.decimals != nil {
+		return *t.decimals, nil
+	}
+
+	out, err := t.call("decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimals := *abi.ConvertType(out, new(uint8)).(*uint8)
+	t.decimals = &decimals
+
+	return decimals, nil
+}
+
+// TotalSupply retrieves the total supply of the token.
+//
+// @return the total supply in base units, or an error if the call fails
+func (t *ERC20) TotalSupply() (sdkmath.Int, error) {
+	return t.callAmount("totalSupply")
+}
+
+// BalanceOf retrieves the token balance of an address.
+//
+// @param owner the 0x or cysic address
+// @return the balance in base units, or an error if the address is invalid or the call fails
+func (t *ERC20) BalanceOf(owner string) (sdkmath.Int, error) {
+	ownerAddr, err := parseEthAddress(owner)
+	if err != nil {
+		return sdkmath.Int{}, err
+	}
+
+	return t.callAmount("balanceOf", ownerAddr)
+}
+
+// GetBalance retrieves the token balance of an address in display units, like Server.GetBalance does for bank balances.
+//
+// @param owner the 0x or cysic address
+// @return the balance as a string, or an error if the address is invalid or a call fails
+func (t *ERC20) GetBalance(owner string) (string, error) {
+	amount, err := t.BalanceOf(owner)
+	if err != nil {
+		return "0", err
+	}
+	decimals, err := t.Decimals()
+	if err != nil {
+		return "0", err
+	}
+
+	return FromBaseUnits(amount, uint32(decimals)).String(), nil
+}
+
+// Allowance retrieves the amount a spender may transfer on behalf of an owner.
+//
+// @param owner the 0x or cysic address of the owner
+// @param spender the 0x or cysic address of the spender
+// @return the allowance in base units, or an error if an address is invalid or the call fails
+func (t *ERC20) Allowance(owner string, spender string) (sdkmath.Int, error) {
+	ownerAddr, err := parseEthAddress(owner)
+	if err != nil {
+		return sdkmath.Int{}, err
+	}
+	spenderAddr, err := parseEthAddress(spender)
+	if err != nil {
+		return sdkmath.Int{}, err
+	}
+
+	return t.callAmount("allowance", ownerAddr, spenderAddr)
+}
+
+// Transfer transfers tokens from the signer.
+//
+// @param signer the Signer instance of the sender
+// @param toAddr the 0x or cysic address of the recipient
+// @param amount the amount in base units
+// @return the sent transaction, or an error if the input is invalid or the transaction fails
+func (t *ERC20) Transfer(signer Signer, toAddr string, amount sdkmath.Int) (*ethtypes.Transaction, error) {
+	to, err := toEthAddress(toAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.transact(signer, "transfer", amount, to)
+}
+
+// TransferDecimal transfers tokens from the signer, the amount is in display units.
+//
+// @param signer the Signer instance of the sender
+// @param toAddr the 0x or cysic address of the recipient
+// @param amount the amount in display units, e.g. 1.5
+// @return the sent transaction, or an error if the input is invalid or the transaction fails
+func (t *ERC20) TransferDecimal(signer Signer, toAddr string, amount decimal.Decimal) (*ethtypes.Transaction, error) {
+	baseAmount, err := t.toBaseUnits(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Transfer(signer, toAddr, baseAmount)
+}
+
+// Approve allows a spender to transfer tokens on behalf of the signer.
+//
+// @param signer the Signer instance of the owner
+// @param spender the 0x or cysic address of the spender
+// @param amount the allowance in base units
+// @return the sent transaction, or an error if the input is invalid or the transaction fails
+func (t *ERC20) Approve(signer Signer, spender string, amount sdkmath.Int) (*ethtypes.Transaction, error) {
+	spenderAddr, err := toEthAddress(spender)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.transact(signer, "approve", amount, spenderAddr)
+}
+
+// TransferFrom transfers tokens from an owner that approved the signer.
+//
+// @param signer the Signer instance of the spender
+// @param fromAddr the 0x or cysic address of the owner
+// @param toAddr the 0x or cysic address of the recipient
+// @param amount the amount in base units
+// @return the sent transaction, or an error if the input is invalid or the transaction fails
+func (t *ERC20) TransferFrom(signer Signer, fromAddr string, toAddr string, amount sdkmath.Int) (*ethtypes.Transaction, error) {
+	from, err := toEthAddress(fromAddr)
+	if err != nil {
+		return nil, err
+	}
+	to, err := toEthAddress(toAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.transact(signer, "transferFrom", amount, from, to)
+}
+
+// transact sends a call of method with the addresses followed by the amount as arguments.
+func (t *ERC20) transact(signer Signer, method string, amount sdkmath.Int, addrs ...common.Address) (*ethtypes.Transaction, error) {
+	if amount.IsNil() || amount.IsNegative() {
+		return nil, fmt.Errorf("amount can't be negative: %v", amount)
+	}
+
+	args := make([]interface{}, 0, len(addrs)+1)
+	for _, addr := range addrs {
+		args = append(args, addr)
+	}
+	args = append(args, amount.BigInt())
+
+	data, err := parsedERC20ABI.Pack(method, args...)
+	if err != nil {
+		log.Printf("error when pack %v of erc20 %v, err: %v", method, t.Address.Hex(), err.Error())
+		return nil, err
+	}
+
+	return t.client.SendEVMTx(signer, EVMTxParams{To: &t.Address, Data: data})
+}
+
+func (t *ERC20) toBaseUnits(amount decimal.Decimal) (sdkmath.Int, error) {
+	decimals, err := t.Decimals()
+	if err != nil {
+		return sdkmath.Int{}, err
+	}
+
+	return ToBaseUnits(amount, uint32(decimals))
+}
+
+// FilterTransfers scans the Transfer events of the token in a block range.
+//
+// @param fromAddrs the 0x or cysic addresses of the senders to match, empty matches any sender, the zero address matches mints
+// @param toAddrs the 0x or cysic addresses of the recipients to match, empty matches any recipient, the zero address matches burns
+// @param fromBlock the first block
+// @param toBlock the last block, nil for the latest block
+// @return the transfers ordered by block and log index, or an error if an address is invalid or the query fails
+func (t *ERC20) FilterTransfers(fromAddrs []string, toAddrs []string, fromBlock uint64, toBlock *uint64) ([]ERC20Transfer, error) {
+	fromTopics, err := addressTopics(fromAddrs)
+	if err != nil {
+		return nil, err
+	}
+	toTopics, err := addressTopics(toAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		Addresses: []common.Address{t.Address},
+		Topics:    [][]common.Hash{{parsedERC20ABI.Events["Transfer"].ID}, fromTopics, toTopics},
+	}
+	if toBlock != nil {
+		filter.ToBlock = new(big.Int).SetUint64(*toBlock)
+	}
+
+	logs, err := t.client.Backend.FilterLogs(context.Background(), filter)
+	if err != nil {
+		log.Printf("could not filter transfers of erc20 %v: %v", t.Address.Hex(), err)
+		return nil, err
+	}
+
+	result := make([]ERC20Transfer, 0, len(logs))
+	for i := range logs {
+		transfer, err := t.ParseTransfer(&logs[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *transfer)
+	}
+
+	return result, nil
+}
+
+// ParseTransfer decodes a Transfer event log of the token.
+//
+// @param eventLog the log
+// @return the transfer, or an error if the log is not a Transfer event
+func (t *ERC20) ParseTransfer(eventLog *ethtypes.Log) (*ERC20Transfer, error) {
+	event := parsedERC20ABI.Events["Transfer"]
+	if len(eventLog.Topics) != 3 || eventLog.Topics[0] != event.ID {
+		return nil, fmt.Errorf("log %v of tx %v is not a transfer event", eventLog.Index, eventLog.TxHash.Hex())
+	}
+
+	values, err := event.Inputs.NonIndexed().Unpack(eventLog.Data)
+	if err != nil {
+		log.Printf("error when unpack transfer event of tx: %v, err: %v", eventLog.TxHash.Hex(), err.Error())
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("transfer event of tx %v has %v values", eventLog.TxHash.Hex(), len(values))
+	}
+
+	return &ERC20Transfer{
+		TxHash:      eventLog.TxHash.Hex(),
+		BlockNumber: eventLog.BlockNumber,
+		LogIndex:    eventLog.Index,
+		Token:       eventLog.Address.Hex(),
+		From:        common.BytesToAddress(eventLog.Topics[1].Bytes()).Hex(),
+		To:          common.BytesToAddress(eventLog.Topics[2].Bytes()).Hex(),
+		Amount:      sdkmath.NewIntFromBigInt(*abi.ConvertType(values[0], new(*big.Int)).(**big.Int)),
+		Removed:     eventLog.Removed,
+		Log:         eventLog,
+	}, nil
+}
+
+// GetTokenBalance retrieves the balance of an address in display units, from the bank module for a denom
+// or from the ERC-20 contract for a 0x token address.
+//
+// @param evm the EVM client, only used for ERC-20 tokens
+// @param address the 0x or cysic address
+// @param token a bank denom, e.g. CGT, or the 0x address of an ERC-20 contract
+// @return the balance as a string, or an error if a query fails
+func (s *Server) GetTokenBalance(evm *EVMClient, address string, token string) (string, error) {
+	if !common.IsHexAddress(token) {
+		return s.GetBalance(address, token)
+	}
+	if evm == nil {
+		return "0", fmt.Errorf("an evm client is required for erc20 token %v", token)
+	}
+
+	erc20, err := evm.NewERC20(token)
+	if err != nil {
+		return "0", err
+	}
+
+	return erc20.GetBalance(address)
+}
+
+// addressTopics converts addresses to the topics of indexed address arguments, the zero address is allowed.
+func addressTopics(addrs []string) ([]common.Hash, error) {
+	topics := make([]common.Hash, 0, len(addrs))
+	for _, addr := range addrs {
+		ethAddr, err := parseEthAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		topics = append(topics, common.BytesToHash(ethAddr.Bytes()))
+	}
+
+	return topics, nil
+}
+
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
@@ -0,0 +1,48 @@
+package gosdk
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAddressTopics(t *testing.T) {
+	holder := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	holderCysic, err := ConvertToCysicAddress(holder.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		addrs   []string
+		want    []common.Hash
+		wantErr bool
+	}{
+		{name: "empty", addrs: nil, want: []common.Hash{}},
+		{name: "hex", addrs: []string{holder.Hex()}, want: []common.Hash{common.BytesToHash(holder.Bytes())}},
+		{name: "bech32", addrs: []string{holderCysic}, want: []common.Hash{common.BytesToHash(holder.Bytes())}},
+		{name: "zero address matches mints", addrs: []string{common.Address{}.Hex()}, want: []common.Hash{{}}},
+		{name: "invalid bech32", addrs: []string{holder.Hex(), "cysic1qqqq"}, wantErr: true},
+		{name: "unknown format", addrs: []string{"holder"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addressTopics(tt.addrs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("addressTopics() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("addressTopics() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("addressTopics()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
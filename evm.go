@@ -0,0 +1,351 @@
+package gosdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/hack2fun/gosdk/crypto/ethsecp256k1"
+	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const defaultReceiptPollInterval = time.Second
+
+// EVMBackend is the EVM node an EVMClient talks to, e.g. an *ethclient.Client or go-ethereum's
+// *backends.SimulatedBackend.
+type EVMBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// EVMClient sends transactions and contract calls to the EVM side of the chain, signed with the key of a Signer.
+type EVMClient struct {
+	Backend EVMBackend
+	// ChainID is the EIP-155 chain ID transactions are signed for
+	ChainID *big.Int
+}
+
+// EVMTxParams describes an EVM transaction, zero fields are filled from the backend.
+type EVMTxParams struct {
+	// To is the recipient, nil deploys a contract
+	To    *common.Address
+	Value *big.Int
+	Data  []byte
+	// GasLimit is estimated when 0
+	GasLimit uint64
+	// Legacy signs a legacy transaction priced with GasPrice instead of an EIP-1559 transaction
+	Legacy   bool
+	GasPrice *big.Int
+	// GasTipCap and GasFeeCap price EIP-1559 transactions, the fee cap defaults to the tip plus twice the base fee
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	// Nonce is the pending nonce of the signer when nil
+	Nonce *uint64
+}
+
+// NewEVMClient connects to the JSON-RPC endpoint of the chain, signing for the EIP-155 chain ID of the Server.
+//
+// @param rpcURL the JSON-RPC endpoint, e.g. http://127.0.0.1:8545
+// @return a new EVMClient instance, or an error if the chain ID is invalid or the connection fails
+func (s *Server) NewEVMClient(rpcURL string) (*EVMClient, error) {
+	chainID, err := cysicTypes.ParseChainID(s.ChainID)
+	if err != nil {
+		log.Printf("error when parse chain id: %v, err: %v", s.ChainID, err.Error())
+		return nil, err
+	}
+
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		log.Printf("error when dial json-rpc: %v, err: %v", rpcURL, err.Error())
+		return nil, err
+	}
+
+	return NewEVMClientWithBackend(client, chainID), nil
+}
+
+// NewEVMClientWithBackend creates a new EVMClient on a given backend, e.g. a simulated backend in tests.
+//
+// @param backend the EVM backend
+// @param chainID the EIP-155 chain ID, 1337 for the simulated backend
+// @return a new EVMClient instance
+func NewEVMClientWithBackend(backend EVMBackend, chainID *big.Int) *EVMClient {
+	return &EVMClient{
+		Backend: backend,
+		ChainID: chainID,
+	}
+}
+
+// ecdsaKey returns the ethsecp256k1 key of a signer as an ECDSA key.
+func (signer Signer) ecdsaKey() (*ecdsa.PrivateKey, error) {
+	key, ok := signer.privateKey.(*ethsecp256k1.PrivKey)
+	if !ok {
+		return nil, fmt.Errorf("signer key is %T, not an ethsecp256k1 key", signer.privateKey)
+	}
+
+	return key.ToECDSA()
+}
+
+// TransactOpts creates the options to send contract transactions through go-ethereum bind with the key of a signer.
+//
+// @param signer the Signer instance used to sign the transactions
+// @return the transact options, or an error if the signer has no ethsecp256k1 key
+func (c *EVMClient) TransactOpts(signer Signer) (*bind.TransactOpts, error) {
+	key, err := signer.ecdsaKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return bind.NewKeyedTransactorWithChainID(key, c.ChainID)
+}
+
+// CallOpts creates the options to call contracts through go-ethereum bind on behalf of a signer.
+//
+// @param signer the Signer instance the calls are made from
+// @return the call options
+func (c *EVMClient) CallOpts(signer Signer) *bind.CallOpts {
+	return &bind.CallOpts{From: signer.EthAddr, Context: context.Background()}
+}
+
+// GetEVMNonce retrieves the pending nonce of an address.
+//
+// @param addr the 0x or cysic address
+// @return the nonce, or an error if the address is invalid or the query fails
+func (c *EVMClient) GetEVMNonce(addr string) (uint64, error) {
+	ethAddr, err := parseEthAddress(addr)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.Backend.PendingNonceAt(context.Background(), ethAddr)
+}
+
+// GetEVMBalance retrieves the native balance of an address, in base units, at the latest block.
+//
+// @param addr the 0x or cysic address
+// @return the balance, or an error if the address is invalid or the query fails
+func (c *EVMClient) GetEVMBalance(addr string) (*big.Int, error) {
+	ethAddr, err := parseEthAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Backend.BalanceAt(context.Background(), ethAddr, nil)
+}
+
+// GetReceipt retrieves the receipt of a transaction.
+//
+// @param txHash the transaction hash
+// @return the receipt, or ethereum.NotFound if the transaction is not mined yet
+func (c *EVMClient) GetReceipt(txHash common.Hash) (*ethtypes.Receipt, error) {
+	return c.Backend.TransactionReceipt(context.Background(), txHash)
+}
+
+// WaitReceipt polls the receipt of a transaction until it is mined or the timeout expires.
+//
+// @param txHash the transaction hash
+// @param timeout the maximum time to wait
+// @return the receipt, or an error if the transaction is not mined in time
+func (c *EVMClient) WaitReceipt(txHash common.Hash, timeout time.Duration) (*ethtypes.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(defaultReceiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := c.Backend.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			log.Printf("could not query receipt of %v: %v", txHash.Hex(), err)
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %v is not mined after %v", txHash.Hex(), timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// SignEVMTx builds and signs an EIP-1559 or legacy transaction with the key of a signer.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param params the transaction
+// @return the signed transaction, or an error if a field can't be filled or signing fails
+func (c *EVMClient) SignEVMTx(signer Signer, params EVMTxParams) (*ethtypes.Transaction, error) {
+	key, err := signer.ecdsaKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	value := params.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	var nonce uint64
+	if params.Nonce != nil {
+		nonce = *params.Nonce
+	} else {
+		nonce, err = c.Backend.PendingNonceAt(ctx, signer.EthAddr)
+		if err != nil {
+			log.Printf("could not query nonce of %v: %v", signer.EthAddr.Hex(), err)
+			return nil, err
+		}
+	}
+
+	gasLimit := params.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = c.Backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:  signer.EthAddr,
+			To:    params.To,
+			Value: value,
+			Data:  params.Data,
+		})
+		if err != nil {
+			log.Printf("could not estimate gas: %v", err)
+			return nil, err
+		}
+	}
+
+	var txData ethtypes.TxData
+	if params.Legacy {
+		gasPrice := params.GasPrice
+		if gasPrice == nil {
+			gasPrice, err = c.Backend.SuggestGasPrice(ctx)
+			if err != nil {
+				log.Printf("could not suggest gas price: %v", err)
+				return nil, err
+			}
+		}
+		txData = &ethtypes.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasPrice,
+			Gas:      gasLimit,
+			To:       params.To,
+			Value:    value,
+			Data:     params.Data,
+		}
+	} else {
+		gasTipCap, gasFeeCap, err := c.suggestFees(ctx, params.GasTipCap, params.GasFeeCap)
+		if err != nil {
+			return nil, err
+		}
+		txData = &ethtypes.DynamicFeeTx{
+			ChainID:   c.ChainID,
+			Nonce:     nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gasLimit,
+			To:        params.To,
+			Value:     value,
+			Data:      params.Data,
+		}
+	}
+
+	return ethtypes.SignNewTx(key, ethtypes.LatestSignerForChainID(c.ChainID), txData)
+}
+
+func (c *EVMClient) suggestFees(ctx context.Context, gasTipCap *big.Int, gasFeeCap *big.Int) (*big.Int, *big.Int, error) {
+	var err error
+	if gasTipCap == nil {
+		gasTipCap, err = c.Backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			log.Printf("could not suggest gas tip cap: %v", err)
+			return nil, nil, err
+		}
+	}
+	if gasFeeCap == nil {
+		head, err := c.Backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			log.Printf("could not query latest header: %v", err)
+			return nil, nil, err
+		}
+		gasFeeCap = new(big.Int).Set(gasTipCap)
+		if head.BaseFee != nil {
+			gasFeeCap.Add(gasFeeCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+		}
+	}
+	if gasFeeCap.Cmp(gasTipCap) < 0 {
+		return nil, nil, fmt.Errorf("gas fee cap %v is below gas tip cap %v", gasFeeCap, gasTipCap)
+	}
+
+	return gasTipCap, gasFeeCap, nil
+}
+
+// SendEVMTx signs a transaction with the key of a signer and sends it.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param params the transaction
+// @return the sent transaction, or an error if signing or sending fails
+func (c *EVMClient) SendEVMTx(signer Signer, params EVMTxParams) (*ethtypes.Transaction, error) {
+	tx, err := c.SignEVMTx(signer, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Backend.SendTransaction(context.Background(), tx); err != nil {
+		log.Printf("error when send evm tx: %v, err: %v", tx.Hash().Hex(), err.Error())
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// TransferEVM sends native tokens on the EVM side.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param toAddr the 0x or cysic address of the recipient
+// @param amount the amount in base units
+// @return the sent transaction, or an error if the address is invalid or the transaction fails
+func (c *EVMClient) TransferEVM(signer Signer, toAddr string, amount *big.Int) (*ethtypes.Transaction, error) {
+	to, err := toEthAddress(toAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SendEVMTx(signer, EVMTxParams{To: &to, Value: amount})
+}
+
+// parseEthAddress converts a 0x or cysic address to a common.Address, rejecting bech32 addresses that don't decode.
+func parseEthAddress(addr string) (common.Address, error) {
+	accAddr, err := toAccAddress(addr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(accAddr) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("addr %v is %v bytes, expected %v", addr, len(accAddr), common.AddressLength)
+	}
+
+	return common.BytesToAddress(accAddr), nil
+}
+
+// toEthAddress converts a 0x or cysic address to a common.Address like parseEthAddress, and also rejects the
+// zero address, which is never a valid recipient.
+func toEthAddress(addr string) (common.Address, error) {
+	ethAddr, err := parseEthAddress(addr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if ethAddr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("addr %v is the zero address", addr)
+	}
+
+	return ethAddr, nil
+}
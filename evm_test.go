@@ -0,0 +1,164 @@
+package gosdk
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// stopContractCode deploys a contract whose runtime code is a single STOP.
+var stopContractCode = common.FromHex("0x6001600c60003960016000f300")
+
+func newSimulatedEVMClient(t *testing.T) (*EVMClient, *backends.SimulatedBackend, *Signer) {
+	t.Helper()
+
+	signer := NewSignerWithPrivateKey(common.FromHex("0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"))
+	balance := new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{signer.EthAddr: {Balance: balance}}, 8000000)
+	t.Cleanup(func() { backend.Close() })
+
+	return NewEVMClientWithBackend(backend, big.NewInt(1337)), backend, signer
+}
+
+func TestToEthAddress(t *testing.T) {
+	holder := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	holderCysic, err := ConvertToCysicAddress(holder.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		addr    string
+		want    common.Address
+		wantErr bool
+	}{
+		{name: "hex", addr: holder.Hex(), want: holder},
+		{name: "bech32", addr: holderCysic, want: holder},
+		{name: "invalid bech32", addr: "cysic1qqqq", wantErr: true},
+		{name: "zero address", addr: common.Address{}.Hex(), wantErr: true},
+		{name: "empty", addr: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toEthAddress(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toEthAddress(%q) err = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("toEthAddress(%q) = %v, want %v", tt.addr, got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}
+
+func TestEVMClientSendTx(t *testing.T) {
+	client, backend, signer := newSimulatedEVMClient(t)
+
+	deployTx, err := client.SendEVMTx(*signer, EVMTxParams{Data: stopContractCode})
+	if err != nil {
+		t.Fatalf("deploy contract: %v", err)
+	}
+	backend.Commit()
+	deployReceipt, err := client.WaitReceipt(deployTx.Hash(), time.Second)
+	if err != nil {
+		t.Fatalf("wait deploy receipt: %v", err)
+	}
+	if deployReceipt.Status != ethtypes.ReceiptStatusSuccessful || deployReceipt.ContractAddress == (common.Address{}) {
+		t.Fatalf("deploy receipt status = %v, contract = %v", deployReceipt.Status, deployReceipt.ContractAddress.Hex())
+	}
+	contract := deployReceipt.ContractAddress
+
+	tests := []struct {
+		name   string
+		params EVMTxParams
+		txType uint8
+	}{
+		{name: "eip-1559", params: EVMTxParams{To: &contract, Value: big.NewInt(1000)}, txType: ethtypes.DynamicFeeTxType},
+		{name: "legacy", params: EVMTxParams{To: &contract, Value: big.NewInt(2000), Legacy: true}, txType: ethtypes.LegacyTxType},
+		{name: "legacy with gas price", params: EVMTxParams{To: &contract, Legacy: true, GasPrice: big.NewInt(2000000000), GasLimit: 50000}, txType: ethtypes.LegacyTxType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := client.GetEVMBalance(contract.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tx, err := client.SendEVMTx(*signer, tt.params)
+			if err != nil {
+				t.Fatalf("SendEVMTx() err = %v", err)
+			}
+			if tx.Type() != tt.txType {
+				t.Errorf("tx type = %v, want %v", tx.Type(), tt.txType)
+			}
+			sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(client.ChainID), tx)
+			if err != nil || sender != signer.EthAddr {
+				t.Fatalf("tx sender = %v, err = %v, want %v", sender.Hex(), err, signer.EthAddr.Hex())
+			}
+
+			backend.Commit()
+			receipt, err := client.GetReceipt(tx.Hash())
+			if err != nil {
+				t.Fatalf("GetReceipt() err = %v", err)
+			}
+			if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+				t.Fatalf("receipt status = %v", receipt.Status)
+			}
+
+			after, err := client.GetEVMBalance(contract.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := tt.params.Value
+			if value == nil {
+				value = new(big.Int)
+			}
+			if got := new(big.Int).Sub(after, before); got.Cmp(value) != 0 {
+				t.Errorf("contract received %v, want %v", got, value)
+			}
+		})
+	}
+}
+
+func TestEVMClientTransfer(t *testing.T) {
+	client, backend, signer := newSimulatedEVMClient(t)
+	recipient := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	recipientCysic, err := ConvertToCysicAddress(recipient.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := client.TransferEVM(*signer, recipientCysic, big.NewInt(12345))
+	if err != nil {
+		t.Fatalf("TransferEVM() err = %v", err)
+	}
+	backend.Commit()
+	if _, err := client.WaitReceipt(tx.Hash(), time.Second); err != nil {
+		t.Fatalf("WaitReceipt() err = %v", err)
+	}
+
+	balance, err := client.GetEVMBalance(recipient.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(big.NewInt(12345)) != 0 {
+		t.Errorf("recipient balance = %v, want 12345", balance)
+	}
+	nonce, err := client.GetEVMNonce(signer.CosmosAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 1 {
+		t.Errorf("signer nonce = %v, want 1", nonce)
+	}
+
+	if _, err := client.TransferEVM(*signer, common.Address{}.Hex(), big.NewInt(1)); err == nil {
+		t.Errorf("TransferEVM() to the zero address succeeded")
+	}
+}
@@ -1,12 +1,16 @@
 package gosdk
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
 	govTokenTypes "github.com/hack2fun/gosdk/types/govtoken"
 
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
 )
 
 // ExchangeToCGT exchanges tokens to governance tokens.
@@ -62,3 +66,158 @@ func (s *Server) ExchangeToCYS(signer Signer, exchangeDetail *govTokenTypes.MsgE
 
 	return txHash, nil
 }
+
+// ErrExchangeBelowMinOutput is returned when an exchange yields, or is quoted to yield, less than the minimum output.
+var ErrExchangeBelowMinOutput = errors.New("exchange output below minimum")
+
+// ExchangeQuote is the expected output of an exchange at the current on-chain rate.
+type ExchangeQuote struct {
+	FromDenom      string          `json:"from_denom"`
+	ToDenom        string          `json:"to_denom"`
+	Amount         math.Int        `json:"amount"`
+	Rate           decimal.Decimal `json:"rate"`
+	ExpectedOutput math.Int        `json:"expected_output"`
+}
+
+// ExchangeResult is the outcome of an exchange compared with its quote.
+type ExchangeResult struct {
+	TxHash         string         `json:"tx_hash"`
+	Quote          *ExchangeQuote `json:"quote"`
+	ReceivedAmount math.Int       `json:"received_amount"`
+	// Deviation is the received amount minus the expected output
+	Deviation math.Int `json:"deviation"`
+	Deviated  bool     `json:"deviated"`
+}
+
+// QuoteExchangeToCGT computes the CGT expected for exchanging CYS at the current rate.
+//
+// @param amount the amount of CYS to exchange
+// @return the quote, or an error if the rate can't be queried
+func (s *Server) QuoteExchangeToCGT(amount math.Int) (*ExchangeQuote, error) {
+	return s.quoteExchange(CYSToken, CGTToken, amount)
+}
+
+// QuoteExchangeToCYS computes the CYS expected for exchanging CGT at the current rate.
+//
+// @param amount the amount of CGT to exchange
+// @return the quote, or an error if the rate can't be queried
+func (s *Server) QuoteExchangeToCYS(amount math.Int) (*ExchangeQuote, error) {
+	return s.quoteExchange(CGTToken, CYSToken, amount)
+}
+
+func (s *Server) quoteExchange(fromDenom string, toDenom string, amount math.Int) (*ExchangeQuote, error) {
+	if amount.IsNil() || !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive: %v", amount)
+	}
+
+	rate, reverseRate, err := s.queryExchangeRates(fromDenom, toDenom)
+	if err != nil {
+		log.Printf("error when get exchange rate from %v to %v, err: %v", fromDenom, toDenom, err.Error())
+		return nil, err
+	}
+
+	return newExchangeQuote(fromDenom, toDenom, amount, rate, reverseRate), nil
+}
+
+// newExchangeQuote computes the output the way the govtoken module does: amount * rate, or
+// amount / reverseRate truncated when only the reverse direction has a rate.
+func newExchangeQuote(fromDenom string, toDenom string, amount math.Int, rate uint64, reverseRate uint64) *ExchangeQuote {
+	quote := &ExchangeQuote{
+		FromDenom: fromDenom,
+		ToDenom:   toDenom,
+		Amount:    amount,
+		Rate:      exchangeRateOf(rate, reverseRate),
+	}
+	if rate != 0 {
+		quote.ExpectedOutput = amount.Mul(math.NewIntFromUint64(rate))
+	} else {
+		quote.ExpectedOutput = amount.Quo(math.NewIntFromUint64(reverseRate))
+	}
+
+	return quote
+}
+
+// ExchangeToCGTWithMinOutput exchanges CYS to CGT, refusing to sign if the current rate yields less than minOutput.
+//
+// The rate is queried right before signing, after the message is built. Since the owner can change the
+// rate until the transaction is executed, the received amount is compared with the quote afterwards: any
+// difference is flagged in the result, and ErrExchangeBelowMinOutput is returned with the result if it
+// is below minOutput.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param amount the amount of CYS to exchange
+// @param minOutput the minimum acceptable amount of CGT
+// @return the exchange result, or an error if the quote is below minOutput or the exchange fails
+func (s *Server) ExchangeToCGTWithMinOutput(signer Signer, amount math.Int, minOutput math.Int) (*ExchangeResult, error) {
+	msg := &govTokenTypes.MsgExchangeToGovToken{
+		Sender: signer.CosmosAddr.String(),
+		Amount: amount,
+	}
+	resp := &govTokenTypes.MsgExchangeToGovTokenResponse{}
+	quote := func() (*ExchangeQuote, error) { return s.QuoteExchangeToCGT(amount) }
+
+	return s.exchangeWithMinOutput(signer, quote, minOutput, msg, resp, func() math.Int { return resp.ReceivedAmount })
+}
+
+// ExchangeToCYSWithMinOutput exchanges CGT to CYS, refusing to sign if the current rate yields less than minOutput.
+//
+// See ExchangeToCGTWithMinOutput for how deviations are reported.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param amount the amount of CGT to exchange
+// @param minOutput the minimum acceptable amount of CYS
+// @return the exchange result, or an error if the quote is below minOutput or the exchange fails
+func (s *Server) ExchangeToCYSWithMinOutput(signer Signer, amount math.Int, minOutput math.Int) (*ExchangeResult, error) {
+	msg := &govTokenTypes.MsgExchangeToPlatformToken{
+		Sender: signer.CosmosAddr.String(),
+		Amount: amount,
+	}
+	resp := &govTokenTypes.MsgExchangeToPlatformTokenResponse{}
+	quote := func() (*ExchangeQuote, error) { return s.QuoteExchangeToCYS(amount) }
+
+	return s.exchangeWithMinOutput(signer, quote, minOutput, msg, resp, func() math.Int { return resp.ReceivedAmount })
+}
+
+// exchangeWithMinOutput quotes the exchange right before broadcasting msg, then compares the received amount with the quote.
+func (s *Server) exchangeWithMinOutput(signer Signer, quoteExchange func() (*ExchangeQuote, error), minOutput math.Int, msg sdk.Msg, resp codec.ProtoMarshaler, receivedAmount func() math.Int) (*ExchangeResult, error) {
+	if minOutput.IsNil() || minOutput.IsNegative() {
+		return nil, fmt.Errorf("min output can't be nil or negative: %v", minOutput)
+	}
+
+	quote, err := quoteExchange()
+	if err != nil {
+		return nil, err
+	}
+	if quote.ExpectedOutput.LT(minOutput) {
+		return nil, fmt.Errorf("%w: quoted %v%v, min %v%v", ErrExchangeBelowMinOutput, quote.ExpectedOutput, quote.ToDenom, minOutput, quote.ToDenom)
+	}
+
+	txHash, txResp, err := s.broadcastAndWaitMsg(signer, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := DecodeMsgResponse(txResp, 0, resp); err != nil {
+		log.Printf("error when decode exchange response of tx: %v, err: %v", txHash, err.Error())
+		return nil, err
+	}
+
+	result := &ExchangeResult{
+		TxHash:         txHash,
+		Quote:          quote,
+		ReceivedAmount: receivedAmount(),
+	}
+	if result.ReceivedAmount.IsNil() {
+		result.ReceivedAmount = math.ZeroInt()
+	}
+	result.Deviation = result.ReceivedAmount.Sub(quote.ExpectedOutput)
+	result.Deviated = !result.Deviation.IsZero()
+	if result.Deviated {
+		log.Printf("exchange tx: %v received %v%v, quoted %v%v", txHash, result.ReceivedAmount, quote.ToDenom, quote.ExpectedOutput, quote.ToDenom)
+	}
+	if result.ReceivedAmount.LT(minOutput) {
+		return result, fmt.Errorf("%w: received %v%v, min %v%v", ErrExchangeBelowMinOutput, result.ReceivedAmount, quote.ToDenom, minOutput, quote.ToDenom)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package gosdk
+
+import (
+	"errors"
+	"testing"
+
+	"cosmossdk.io/math"
+)
+
+func TestExchangeWithMinOutputValidation(t *testing.T) {
+	quoteErr := errors.New("quote failed")
+	quote := func(output int64) func() (*ExchangeQuote, error) {
+		return func() (*ExchangeQuote, error) {
+			return &ExchangeQuote{FromDenom: CYSToken, ToDenom: CGTToken, Amount: math.NewInt(100), ExpectedOutput: math.NewInt(output)}, nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		quote     func() (*ExchangeQuote, error)
+		minOutput math.Int
+		wantErr   error
+	}{
+		{name: "nil min output", quote: quote(100), minOutput: math.Int{}},
+		{name: "negative min output", quote: quote(100), minOutput: math.NewInt(-1)},
+		{name: "quote fails", quote: func() (*ExchangeQuote, error) { return nil, quoteErr }, minOutput: math.NewInt(1), wantErr: quoteErr},
+		{name: "quote below min output", quote: quote(99), minOutput: math.NewInt(100), wantErr: ErrExchangeBelowMinOutput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+			result, err := server.exchangeWithMinOutput(Signer{}, tt.quote, tt.minOutput, nil, nil, nil)
+			if err == nil || result != nil {
+				t.Fatalf("exchangeWithMinOutput() = %v, %v, want an error", result, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("exchangeWithMinOutput() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewExchangeQuote(t *testing.T) {
+	oneCGT, _ := math.NewIntFromString("1000000000000000000")
+	threeCYS, _ := math.NewIntFromString("3000000000000000000")
+
+	tests := []struct {
+		name        string
+		amount      math.Int
+		rate        uint64
+		reverseRate uint64
+		want        math.Int
+		wantRate    string
+	}{
+		{name: "direct rate", amount: math.NewInt(100), rate: 3, want: math.NewInt(300), wantRate: "3"},
+		{name: "reverse rate", amount: threeCYS, reverseRate: 3, want: oneCGT, wantRate: "0.333333333333333333"},
+		{name: "reverse rate truncates", amount: math.NewInt(10), reverseRate: 3, want: math.NewInt(3), wantRate: "0.333333333333333333"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := newExchangeQuote(CYSToken, CGTToken, tt.amount, tt.rate, tt.reverseRate)
+			if !quote.ExpectedOutput.Equal(tt.want) {
+				t.Errorf("expected output = %v, want %v", quote.ExpectedOutput, tt.want)
+			}
+			if quote.Rate.String() != tt.wantRate {
+				t.Errorf("rate = %v, want %v", quote.Rate, tt.wantRate)
+			}
+		})
+	}
+}
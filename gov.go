@@ -0,0 +1,374 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govutils "github.com/cosmos/cosmos-sdk/x/gov/client/utils"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+)
+
+const (
+	defaultProposalWatcherPollInterval = BlockTime
+	defaultProposalWatcherBufferSize   = 64
+)
+
+// ProposalFilter selects proposals, empty fields match all proposals.
+type ProposalFilter struct {
+	Status    govv1.ProposalStatus
+	Voter     string
+	Depositor string
+}
+
+// SubmitTextProposal submits a text proposal with an initial CGT deposit.
+//
+// @param signer the Signer instance of the proposer
+// @param title the title of the proposal
+// @param description the description of the proposal
+// @param deposit the initial deposit in CGT base units
+// @return the transaction hash and the proposal id, or an error if the input is invalid or the transaction fails
+func (s *Server) SubmitTextProposal(signer Signer, title string, description string, deposit math.Int) (string, uint64, error) {
+	return s.submitLegacyProposal(signer, govv1beta1.NewTextProposal(title, description), deposit)
+}
+
+// SubmitParamChangeProposal submits a proposal changing module parameters, with an initial CGT deposit.
+//
+// @param signer the Signer instance of the proposer
+// @param title the title of the proposal
+// @param description the description of the proposal
+// @param changes the parameter changes, the value of each change is the JSON encoded new value
+// @param deposit the initial deposit in CGT base units
+// @return the transaction hash and the proposal id, or an error if the input is invalid or the transaction fails
+func (s *Server) SubmitParamChangeProposal(signer Signer, title string, description string, changes []paramproposal.ParamChange, deposit math.Int) (string, uint64, error) {
+	return s.submitLegacyProposal(signer, paramproposal.NewParameterChangeProposal(title, description, changes), deposit)
+}
+
+func (s *Server) submitLegacyProposal(signer Signer, content govv1beta1.Content, deposit math.Int) (string, uint64, error) {
+	if deposit.IsNil() || deposit.IsNegative() {
+		return "", 0, fmt.Errorf("deposit can't be nil or negative: %v", deposit)
+	}
+
+	msg, err := govv1beta1.NewMsgSubmitProposal(content, sdk.NewCoins(sdk.NewCoin(CGTToken, deposit)), signer.CosmosAddr)
+	if err != nil {
+		log.Printf("error when new submit proposal msg, err: %v", err.Error())
+		return "", 0, err
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return "", 0, err
+	}
+
+	txHash, txResp, err := s.broadcastAndWaitMsg(signer, msg)
+	if err != nil {
+		return txHash, 0, err
+	}
+
+	var resp govv1beta1.MsgSubmitProposalResponse
+	if err := DecodeMsgResponse(txResp, 0, &resp); err != nil {
+		log.Printf("error when decode submit proposal response of tx: %v, err: %v", txHash, err.Error())
+		return txHash, 0, err
+	}
+
+	return txHash, resp.ProposalId, nil
+}
+
+// DepositProposal adds a CGT deposit to a proposal in its deposit period.
+//
+// @param signer the Signer instance of the depositor
+// @param proposalID the id of the proposal
+// @param amount the deposit in CGT base units
+// @return the transaction hash as a string, or an error if the input is invalid or the deposit fails
+func (s *Server) DepositProposal(signer Signer, proposalID uint64, amount math.Int) (string, error) {
+	if amount.IsNil() || !amount.IsPositive() {
+		return "", fmt.Errorf("deposit amount must be positive: %v", amount)
+	}
+
+	msg := govv1.NewMsgDeposit(signer.CosmosAddr, proposalID, sdk.NewCoins(sdk.NewCoin(CGTToken, amount)))
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.broadcastMsg(signer, msg)
+}
+
+// VoteProposal votes on a proposal in its voting period.
+//
+// @param signer the Signer instance of the voter
+// @param proposalID the id of the proposal
+// @param option the vote option, e.g. govv1.OptionYes
+// @return the transaction hash as a string, or an error if the input is invalid or the vote fails
+func (s *Server) VoteProposal(signer Signer, proposalID uint64, option govv1.VoteOption) (string, error) {
+	msg := govv1.NewMsgVote(signer.CosmosAddr, proposalID, option, "")
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.broadcastMsg(signer, msg)
+}
+
+// VoteProposalWeighted splits the vote on a proposal across several options.
+//
+// @param signer the Signer instance of the voter
+// @param proposalID the id of the proposal
+// @param options the weighted options, the weights must be distinct options summing to 1, e.g. govv1.NewWeightedVoteOption(govv1.OptionYes, sdk.NewDecWithPrec(7, 1))
+// @return the transaction hash as a string, or an error if the input is invalid or the vote fails
+func (s *Server) VoteProposalWeighted(signer Signer, proposalID uint64, options govv1.WeightedVoteOptions) (string, error) {
+	msg := govv1.NewMsgVoteWeighted(signer.CosmosAddr, proposalID, options, "")
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.broadcastMsg(signer, msg)
+}
+
+// GetProposal retrieves a proposal by id.
+//
+// @param proposalID the id of the proposal
+// @return the proposal, or an error if the query fails
+func (s *Server) GetProposal(proposalID uint64) (*govv1.Proposal, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := govv1.NewQueryClient(s.Conn)
+	resp, err := client.Proposal(s.queryContext(), &govv1.QueryProposalRequest{ProposalId: proposalID})
+	if err != nil {
+		log.Printf("could not query proposal %v: %v", proposalID, err)
+		return nil, err
+	}
+
+	return resp.Proposal, nil
+}
+
+// GetProposals retrieves all proposals matching a filter.
+//
+// @param filter the filter, the voter and depositor may be 0x or cysic addresses
+// @return the proposals ordered by id, or an error if an address is invalid or the query fails
+func (s *Server) GetProposals(filter ProposalFilter) ([]*govv1.Proposal, error) {
+	req := &govv1.QueryProposalsRequest{ProposalStatus: filter.Status}
+	if filter.Voter != "" {
+		voter, err := ConvertToCysicAddress(filter.Voter)
+		if err != nil {
+			log.Printf("error when convert addr: %v to cosmosAddr, err: %v", filter.Voter, err.Error())
+			return nil, err
+		}
+		req.Voter = voter
+	}
+	if filter.Depositor != "" {
+		depositor, err := ConvertToCysicAddress(filter.Depositor)
+		if err != nil {
+			log.Printf("error when convert addr: %v to cosmosAddr, err: %v", filter.Depositor, err.Error())
+			return nil, err
+		}
+		req.Depositor = depositor
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := govv1.NewQueryClient(s.Conn)
+
+	result := make([]*govv1.Proposal, 0)
+	var nextKey []byte
+	for {
+		req.Pagination = &query.PageRequest{Key: nextKey}
+		resp, err := client.Proposals(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query proposals: %v", err)
+			return nil, err
+		}
+
+		result = append(result, resp.Proposals...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetProposalTally retrieves the current tally of a proposal, or the final tally once voting ended.
+//
+// @param proposalID the id of the proposal
+// @return the tally, or an error if the query fails
+func (s *Server) GetProposalTally(proposalID uint64) (*govv1.TallyResult, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := govv1.NewQueryClient(s.Conn)
+	resp, err := client.TallyResult(s.queryContext(), &govv1.QueryTallyResultRequest{ProposalId: proposalID})
+	if err != nil {
+		log.Printf("could not query tally of proposal %v: %v", proposalID, err)
+		return nil, err
+	}
+
+	return resp.Tally, nil
+}
+
+// GetProposalVote retrieves the vote of an address on a proposal.
+//
+// @param proposalID the id of the proposal
+// @param voter the 0x or cysic address of the voter
+// @return the vote, or an error if the address is invalid or the voter did not vote
+func (s *Server) GetProposalVote(proposalID uint64, voter string) (*govv1.Vote, error) {
+	cosmosAddr, err := ConvertToCysicAddress(voter)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", voter, err.Error())
+		return nil, err
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := govv1.NewQueryClient(s.Conn)
+	resp, err := client.Vote(s.queryContext(), &govv1.QueryVoteRequest{ProposalId: proposalID, Voter: cosmosAddr})
+	if err != nil {
+		log.Printf("could not query vote of %v on proposal %v: %v", cosmosAddr, proposalID, err)
+		return nil, err
+	}
+
+	return resp.Vote, nil
+}
+
+// GetProposalDeposit retrieves the deposit of an address on a proposal.
+//
+// @param proposalID the id of the proposal
+// @param depositor the 0x or cysic address of the depositor
+// @return the deposit, or an error if the address is invalid or the depositor did not deposit
+func (s *Server) GetProposalDeposit(proposalID uint64, depositor string) (*govv1.Deposit, error) {
+	cosmosAddr, err := ConvertToCysicAddress(depositor)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", depositor, err.Error())
+		return nil, err
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := govv1.NewQueryClient(s.Conn)
+	resp, err := client.Deposit(s.queryContext(), &govv1.QueryDepositRequest{ProposalId: proposalID, Depositor: cosmosAddr})
+	if err != nil {
+		log.Printf("could not query deposit of %v on proposal %v: %v", cosmosAddr, proposalID, err)
+		return nil, err
+	}
+
+	return resp.Deposit, nil
+}
+
+// ProposalWatcherConfig configures a ProposalWatcher.
+type ProposalWatcherConfig struct {
+	// PollInterval is how often the proposals in voting period are polled
+	PollInterval time.Duration
+	// BufferSize is the capacity of the proposal channel
+	BufferSize int
+}
+
+// ProposalWatcher notifies when proposals enter their voting period.
+//
+// Proposals already in voting period when the watcher starts are delivered on the first poll.
+type ProposalWatcher struct {
+	server    *Server
+	config    ProposalWatcherConfig
+	notified  map[uint64]bool
+	proposals chan *govv1.Proposal
+}
+
+// NewProposalWatcher creates a new ProposalWatcher.
+//
+// @param config the watcher configuration
+// @return a new ProposalWatcher instance
+func (s *Server) NewProposalWatcher(config ProposalWatcherConfig) *ProposalWatcher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultProposalWatcherPollInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultProposalWatcherBufferSize
+	}
+
+	return &ProposalWatcher{
+		server:    s,
+		config:    config,
+		notified:  make(map[uint64]bool),
+		proposals: make(chan *govv1.Proposal, config.BufferSize),
+	}
+}
+
+// VotingProposals returns the channel on which proposals entering their voting period are delivered.
+//
+// @return the proposal channel, closed when Run returns
+func (w *ProposalWatcher) VotingProposals() <-chan *govv1.Proposal {
+	return w.proposals
+}
+
+// Run polls the proposals in voting period until ctx is cancelled.
+//
+// @param ctx the context controlling the watcher's lifetime
+// @return the context error once the watcher stops
+func (w *ProposalWatcher) Run(ctx context.Context) error {
+	defer close(w.proposals)
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx); err != nil {
+			log.Printf("error when poll proposals in voting period, err: %v", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ProposalWatcher) poll(ctx context.Context) error {
+	proposals, err := w.server.GetProposals(ProposalFilter{Status: govv1.StatusVotingPeriod})
+	if err != nil {
+		return err
+	}
+
+	for _, proposal := range proposals {
+		if w.notified[proposal.Id] {
+			continue
+		}
+		select {
+		case w.proposals <- proposal:
+			w.notified[proposal.Id] = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// ParseVoteOption parses a vote option such as "yes", "no", "abstain", "no_with_veto" or "VOTE_OPTION_YES".
+//
+// @param option the vote option
+// @return the vote option, or an error if it is unknown
+func ParseVoteOption(option string) (govv1.VoteOption, error) {
+	voteOption, err := govv1.VoteOptionFromString(govutils.NormalizeVoteOption(option))
+	if err != nil {
+		return govv1.OptionEmpty, fmt.Errorf("invalid vote option: %v", option)
+	}
+
+	return voteOption, nil
+}
@@ -0,0 +1,34 @@
+package gosdk
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+)
+
+func TestProposalDepositValidation(t *testing.T) {
+	signer := NewSignerWithPrivateKey([]byte{1})
+	server := &Server{}
+
+	tests := []struct {
+		name   string
+		amount math.Int
+	}{
+		{name: "nil", amount: math.Int{}},
+		{name: "negative", amount: math.NewInt(-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := server.SubmitParamChangeProposal(*signer, "title", "description", nil, tt.amount); err == nil {
+				t.Errorf("SubmitParamChangeProposal() accepted deposit %v", tt.amount)
+			}
+			if _, err := server.DepositProposal(*signer, 1, tt.amount); err == nil {
+				t.Errorf("DepositProposal() accepted deposit %v", tt.amount)
+			}
+		})
+	}
+
+	if _, err := server.DepositProposal(*signer, 1, math.ZeroInt()); err == nil {
+		t.Errorf("DepositProposal() accepted a zero deposit")
+	}
+}
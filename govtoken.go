@@ -0,0 +1,489 @@
+package gosdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+	govTokenTypes "github.com/hack2fun/gosdk/types/govtoken"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultTxWaitTimeout = 6 * BlockTime
+
+// StakeAsValidator converts platform tokens to CGT and stakes them as a new validator operated by the signer.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param stakeDetail the validator details, Sender defaults to the signer and must match it when set; it is not modified
+// @return the transaction hash and the validation result of the chain, or an error if the input is invalid or the transaction fails
+func (s *Server) StakeAsValidator(signer Signer, stakeDetail *govTokenTypes.MsgStakeAsValidator) (string, string, error) {
+	if stakeDetail == nil {
+		return "", "", fmt.Errorf("stakeDetail is nil")
+	}
+	msg := *stakeDetail
+	if msg.Sender == "" {
+		msg.Sender = signer.CosmosAddr.String()
+	} else if msg.Sender != signer.CosmosAddr.String() {
+		return "", "", fmt.Errorf("sender %v does not match signer %v", msg.Sender, signer.CosmosAddr.String())
+	}
+	if err := ValidateStakeAsValidator(&msg); err != nil {
+		return "", "", err
+	}
+
+	txHash, txResp, err := s.broadcastAndWaitMsg(signer, &msg)
+	if err != nil {
+		return txHash, "", err
+	}
+
+	var resp govTokenTypes.MsgStakeAsValidatorResponse
+	if err := DecodeMsgResponse(txResp, 0, &resp); err != nil {
+		log.Printf("error when decode stake as validator response of tx: %v, err: %v", txHash, err.Error())
+		return txHash, "", err
+	}
+
+	return txHash, resp.ValidationResult, nil
+}
+
+// DelegateToValidator converts platform tokens to CGT and delegates them to a validator in one step.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param validatorAddress the address of the validator
+// @param amount the amount of platform tokens to convert and delegate
+// @return the transaction hash and the delegation result of the chain, or an error if the input is invalid or the transaction fails
+func (s *Server) DelegateToValidator(signer Signer, validatorAddress string, amount math.Int) (string, string, error) {
+	msg := &govTokenTypes.MsgDelegateToValidator{
+		Sender:           signer.CosmosAddr.String(),
+		Amount:           amount,
+		ValidatorAddress: validatorAddress,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return "", "", err
+	}
+
+	txHash, txResp, err := s.broadcastAndWaitMsg(signer, msg)
+	if err != nil {
+		return txHash, "", err
+	}
+
+	var resp govTokenTypes.MsgDelegateToValidatorResponse
+	if err := DecodeMsgResponse(txResp, 0, &resp); err != nil {
+		log.Printf("error when decode delegate to validator response of tx: %v, err: %v", txHash, err.Error())
+		return txHash, "", err
+	}
+
+	return txHash, resp.DelegationResult, nil
+}
+
+// ValidateStakeAsValidator checks a MsgStakeAsValidator beyond its ValidateBasic: the commission rates
+// must be decimals with 0 <= rate <= max rate <= 1 and max change rate <= max rate, and the validator
+// pubkey must be an ed25519 public key in one of the forms accepted by ParseValidatorPubkey.
+//
+// @param msg the message to check
+// @return an error describing the first invalid field
+func ValidateStakeAsValidator(msg *govTokenTypes.MsgStakeAsValidator) error {
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	rate, err := sdk.NewDecFromStr(msg.CommissionRate)
+	if err != nil {
+		return fmt.Errorf("invalid commission rate: %v, err: %v", msg.CommissionRate, err)
+	}
+	maxRate, err := sdk.NewDecFromStr(msg.MaxCommissionRate)
+	if err != nil {
+		return fmt.Errorf("invalid max commission rate: %v, err: %v", msg.MaxCommissionRate, err)
+	}
+	maxChangeRate, err := sdk.NewDecFromStr(msg.MaxChangeCommissionRate)
+	if err != nil {
+		return fmt.Errorf("invalid max change commission rate: %v, err: %v", msg.MaxChangeCommissionRate, err)
+	}
+	if err := stakingtypes.NewCommissionRates(rate, maxRate, maxChangeRate).Validate(); err != nil {
+		return err
+	}
+
+	if _, err := ParseValidatorPubkey(msg.ValidatorPubkey); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validatorPubkeyJSON is the JSON form of a consensus pubkey printed by `show-validator`,
+// e.g. {"@type":"/cosmos.crypto.ed25519.PubKey","key":"<base64>"}.
+type validatorPubkeyJSON struct {
+	Type string `json:"@type"`
+	Key  string `json:"key"`
+}
+
+// FormatValidatorPubkey formats a consensus pubkey as expected by MsgStakeAsValidator.
+//
+// @param pubKey the ed25519 consensus pubkey, e.g. returned by GenerateConsensusKey
+// @return the base64 encoded raw pubkey, or an error if the pubkey is not ed25519
+func FormatValidatorPubkey(pubKey cryptotypes.PubKey) (string, error) {
+	if _, ok := pubKey.(*ed25519.PubKey); !ok {
+		return "", fmt.Errorf("validator pubkey must be ed25519, got: %T", pubKey)
+	}
+
+	return base64.StdEncoding.EncodeToString(pubKey.Bytes()), nil
+}
+
+// ParseValidatorPubkey parses an ed25519 consensus pubkey, either base64 encoded raw bytes as returned by
+// FormatValidatorPubkey, or the JSON form printed by `show-validator`.
+//
+// @param pubkey the base64 encoded pubkey or its JSON form
+// @return the pubkey, or an error if it is malformed or not ed25519
+func ParseValidatorPubkey(pubkey string) (cryptotypes.PubKey, error) {
+	encoded := strings.TrimSpace(pubkey)
+	if strings.HasPrefix(encoded, "{") {
+		var pk validatorPubkeyJSON
+		if err := json.Unmarshal([]byte(encoded), &pk); err != nil {
+			return nil, fmt.Errorf("invalid validator pubkey json, err: %v", err)
+		}
+		if typeURL := "/" + proto.MessageName(&ed25519.PubKey{}); pk.Type != typeURL {
+			return nil, fmt.Errorf("validator pubkey must be %v, got: %v", typeURL, pk.Type)
+		}
+		encoded = pk.Key
+	}
+
+	bz, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("validator pubkey must be base64 encoded, err: %v", err)
+	}
+	if len(bz) != ed25519.PubKeySize {
+		return nil, fmt.Errorf("validator pubkey must be %v bytes, got: %v", ed25519.PubKeySize, len(bz))
+	}
+
+	return &ed25519.PubKey{Key: bz}, nil
+}
+
+// broadcastAndWaitMsg broadcasts a single message and waits for its transaction to be packed.
+func (s *Server) broadcastAndWaitMsg(signer Signer, msg sdk.Msg) (string, *sdk.TxResponse, error) {
+	txHash, err := s.broadcastMsg(signer, msg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	txResp, err := s.WaitTx(txHash, defaultTxWaitTimeout)
+	if err != nil {
+		return txHash, nil, err
+	}
+	if txResp.Code != 0 {
+		return txHash, txResp, fmt.Errorf("tx %v failed, log: %v", txHash, txResp.RawLog)
+	}
+
+	return txHash, txResp, nil
+}
+
+// GetGovTokenOwner retrieves the owner of the govtoken module, the only account allowed to mint, burn, change the owner and set exchange rates.
+//
+// @return the owner address, or an error if the query fails
+func (s *Server) GetGovTokenOwner() (string, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	client := govTokenTypes.NewQueryClient(s.Conn)
+	resp, err := client.Owner(s.queryContext(), &govTokenTypes.QueryOwnerRequest{})
+	if err != nil {
+		log.Printf("could not query govtoken owner: %v", err)
+		return "", err
+	}
+
+	return resp.Owner, nil
+}
+
+// MintGovToken mints governance tokens to a recipient.
+//
+// @param signer the Signer instance of the govtoken owner
+// @param recipient the address receiving the minted tokens
+// @param amount the amount to mint
+// @return the transaction hash and the emitted EventMint, or an error if the signer is not the owner or the mint fails
+func (s *Server) MintGovToken(signer Signer, recipient string, amount math.Int) (string, *govTokenTypes.EventMint, error) {
+	recipientAddr, err := ConvertToCysicAddress(recipient)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", recipient, err.Error())
+		return "", nil, err
+	}
+
+	msg := &govTokenTypes.MsgMint{
+		Owner:     signer.CosmosAddr.String(),
+		Recipient: recipientAddr,
+		Amount:    amount,
+	}
+
+	event := &govTokenTypes.EventMint{}
+	txHash, err := s.broadcastOwnerMsg(signer, msg, event)
+	if err != nil {
+		return txHash, nil, err
+	}
+
+	return txHash, event, nil
+}
+
+// BurnGovToken burns governance tokens held by the signer.
+//
+// @param signer the Signer instance of the govtoken owner
+// @param amount the amount to burn
+// @return the transaction hash and the emitted EventBurn, or an error if the signer is not the owner or the burn fails
+func (s *Server) BurnGovToken(signer Signer, amount math.Int) (string, *govTokenTypes.EventBurn, error) {
+	msg := &govTokenTypes.MsgBurn{
+		Burner: signer.CosmosAddr.String(),
+		Amount: amount,
+	}
+
+	event := &govTokenTypes.EventBurn{}
+	txHash, err := s.broadcastOwnerMsg(signer, msg, event)
+	if err != nil {
+		return txHash, nil, err
+	}
+
+	return txHash, event, nil
+}
+
+// ChangeGovTokenOwner transfers the ownership of the govtoken module.
+//
+// @param signer the Signer instance of the current govtoken owner
+// @param newOwner the address of the new owner
+// @return the transaction hash and the emitted EventOwnerChanged, or an error if the signer is not the owner or the change fails
+func (s *Server) ChangeGovTokenOwner(signer Signer, newOwner string) (string, *govTokenTypes.EventOwnerChanged, error) {
+	newOwnerAddr, err := ConvertToCysicAddress(newOwner)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", newOwner, err.Error())
+		return "", nil, err
+	}
+
+	msg := &govTokenTypes.MsgChangeOwner{
+		OldOwner: signer.CosmosAddr.String(),
+		NewOwner: newOwnerAddr,
+	}
+
+	event := &govTokenTypes.EventOwnerChanged{}
+	txHash, err := s.broadcastOwnerMsg(signer, msg, event)
+	if err != nil {
+		return txHash, nil, err
+	}
+
+	return txHash, event, nil
+}
+
+// SetExchangeRate sets the exchange rate between two denominations.
+//
+// @param signer the Signer instance of the govtoken owner
+// @param fromDenom the denomination exchanged from
+// @param toDenom the denomination exchanged to
+// @param rate the new exchange rate
+// @return the transaction hash as a string, or an error if the signer is not the owner or the operation fails
+func (s *Server) SetExchangeRate(signer Signer, fromDenom string, toDenom string, rate uint64) (string, error) {
+	msg := &govTokenTypes.MsgSetExchangeRate{
+		Owner:     signer.CosmosAddr.String(),
+		FromDenom: fromDenom,
+		ToDenom:   toDenom,
+		Rate:      rate,
+	}
+
+	return s.broadcastOwnerMsg(signer, msg, nil)
+}
+
+// broadcastOwnerMsg checks the signer is the govtoken owner, broadcasts msg, waits for it to be packed,
+// then decodes the typed event emitted by the transaction into event if it is not nil.
+func (s *Server) broadcastOwnerMsg(signer Signer, msg sdk.Msg, event proto.Message) (string, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	owner, err := s.GetGovTokenOwner()
+	if err != nil {
+		return "", err
+	}
+	if owner != signer.CosmosAddr.String() {
+		return "", fmt.Errorf("signer %v is not the govtoken owner %v", signer.CosmosAddr.String(), owner)
+	}
+
+	txHash, txResp, err := s.broadcastAndWaitMsg(signer, msg)
+	if err != nil {
+		return txHash, err
+	}
+
+	if event != nil {
+		if err := DecodeTypedEvent(txResp, event); err != nil {
+			log.Printf("error when decode %v of tx: %v, err: %v", proto.MessageName(event), txHash, err.Error())
+			return txHash, err
+		}
+	}
+
+	return txHash, nil
+}
+
+// GovTokenStatus is a snapshot of the govtoken module.
+type GovTokenStatus struct {
+	Height            int64           `json:"height"`
+	Denom             string          `json:"denom"`
+	Owner             string          `json:"owner"`
+	TotalSupply       math.Int        `json:"total_supply"`
+	PlatformToGovRate decimal.Decimal `json:"platform_to_gov_rate"`
+	GovToPlatformRate decimal.Decimal `json:"gov_to_platform_rate"`
+}
+
+// GetGovTokenParams retrieves the parameters of the govtoken module.
+//
+// @return the govtoken parameters, or an error if the query fails
+func (s *Server) GetGovTokenParams() (*govTokenTypes.Params, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := govTokenTypes.NewQueryClient(s.Conn)
+	resp, err := client.Params(s.queryContext(), &govTokenTypes.QueryParamsRequest{})
+	if err != nil {
+		log.Printf("could not query govtoken params: %v", err)
+		return nil, err
+	}
+	if resp.Params == nil {
+		return &govTokenTypes.Params{}, nil
+	}
+
+	return resp.Params, nil
+}
+
+// GetGovTokenTotalSupply retrieves the total supply of governance tokens tracked by the govtoken module.
+//
+// @return the total supply, or an error if the query fails
+func (s *Server) GetGovTokenTotalSupply() (math.Int, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return math.Int{}, err
+	}
+
+	client := govTokenTypes.NewQueryClient(s.Conn)
+	resp, err := client.TotalSupply(s.queryContext(), &govTokenTypes.QueryTotalSupplyRequest{})
+	if err != nil {
+		log.Printf("could not query govtoken total supply: %v", err)
+		return math.Int{}, err
+	}
+
+	return resp.TotalSupply, nil
+}
+
+// GetExchangeRate retrieves the exchange rate between two denominations as the amount of toDenom received per fromDenom.
+//
+// The chain stores an integer rate for one direction only, when the requested direction has no rate
+// the reverse rate is queried and inverted.
+//
+// @param fromDenom the denomination exchanged from
+// @param toDenom the denomination exchanged to
+// @return the exchange rate, or an error if neither direction has a rate
+func (s *Server) GetExchangeRate(fromDenom string, toDenom string) (decimal.Decimal, error) {
+	rate, reverseRate, err := s.queryExchangeRates(fromDenom, toDenom)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return exchangeRateOf(rate, reverseRate), nil
+}
+
+// exchangeRateOf returns rate, or the inverted reverseRate when rate is 0.
+func exchangeRateOf(rate uint64, reverseRate uint64) decimal.Decimal {
+	if rate != 0 {
+		return decimal.NewFromInt(int64(rate))
+	}
+
+	return decimal.NewFromInt(1).DivRound(decimal.NewFromInt(int64(reverseRate)), cysicTypes.BaseDenomUnit)
+}
+
+// queryExchangeRates returns the integer rate from fromDenom to toDenom, or 0 and the reverse rate
+// when only the reverse direction has a rate.
+func (s *Server) queryExchangeRates(fromDenom string, toDenom string) (uint64, uint64, error) {
+	rate, err := s.queryExchangeRate(fromDenom, toDenom)
+	if err != nil || rate != 0 {
+		return rate, 0, err
+	}
+
+	reverseRate, err := s.queryExchangeRate(toDenom, fromDenom)
+	if err != nil {
+		return 0, 0, err
+	}
+	if reverseRate == 0 {
+		return 0, 0, fmt.Errorf("no exchange rate between %v and %v", fromDenom, toDenom)
+	}
+
+	return 0, reverseRate, nil
+}
+
+func (s *Server) queryExchangeRate(fromDenom string, toDenom string) (uint64, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return 0, err
+	}
+
+	client := govTokenTypes.NewQueryClient(s.Conn)
+	req := &govTokenTypes.QueryExchangeRateRequest{FromDenom: fromDenom, ToDenom: toDenom}
+	resp, err := client.ExchangeRate(s.queryContext(), req)
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+	if err != nil {
+		log.Printf("could not query exchange rate from %v to %v: %v", fromDenom, toDenom, err)
+		return 0, err
+	}
+
+	return resp.Rate, nil
+}
+
+// GetGovTokenStatus retrieves a snapshot of the govtoken module: denom, owner, total supply and exchange rates.
+// Every value is read at the reported height, the height the Server is pinned to or the latest one.
+//
+// @return the govtoken status, or an error if a query fails
+func (s *Server) GetGovTokenStatus() (*GovTokenStatus, error) {
+	height := s.Height()
+	if height <= 0 {
+		latest, err := s.GetLatestBlockHeight()
+		if err != nil {
+			return nil, err
+		}
+		height = latest
+	}
+	// every query reads the same height
+	pinned := s.AtHeight(height)
+
+	// the govtoken module has no denom query and empty params, the governance token is always CGT
+	denom := CGTToken
+	owner, err := pinned.GetGovTokenOwner()
+	if err != nil {
+		return nil, err
+	}
+	totalSupply, err := pinned.GetGovTokenTotalSupply()
+	if err != nil {
+		return nil, err
+	}
+	platformToGovRate, err := pinned.GetExchangeRate(CYSToken, denom)
+	if err != nil {
+		return nil, err
+	}
+	govToPlatformRate, err := pinned.GetExchangeRate(denom, CYSToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GovTokenStatus{
+		Height:            height,
+		Denom:             denom,
+		Owner:             owner,
+		TotalSupply:       totalSupply,
+		PlatformToGovRate: platformToGovRate,
+		GovToPlatformRate: govToPlatformRate,
+	}, nil
+}
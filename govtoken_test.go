@@ -0,0 +1,68 @@
+package gosdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	govTokenTypes "github.com/hack2fun/gosdk/types/govtoken"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func TestParseValidatorPubkey(t *testing.T) {
+	pubKey := ed25519.GenPrivKeyFromSecret([]byte("validator")).PubKey()
+	encoded, err := FormatValidatorPubkey(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pubkey  string
+		wantErr bool
+	}{
+		{name: "base64", pubkey: encoded},
+		{name: "show-validator json", pubkey: `{"@type":"/cosmos.crypto.ed25519.PubKey","key":"` + encoded + `"}`},
+		{name: "show-validator json with newline", pubkey: `{"@type":"/cosmos.crypto.ed25519.PubKey","key":"` + encoded + `"}` + "\n"},
+		{name: "secp256k1 json", pubkey: `{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"` + encoded + `"}`, wantErr: true},
+		{name: "malformed json", pubkey: `{"@type":`, wantErr: true},
+		{name: "not base64", pubkey: "not base64!", wantErr: true},
+		{name: "wrong size", pubkey: base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "empty", pubkey: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValidatorPubkey(tt.pubkey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValidatorPubkey(%q) err = %v, wantErr %v", tt.pubkey, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !bytes.Equal(got.Bytes(), pubKey.Bytes()) {
+				t.Errorf("ParseValidatorPubkey(%q) = %X, want %X", tt.pubkey, got.Bytes(), pubKey.Bytes())
+			}
+		})
+	}
+}
+
+func TestFormatValidatorPubkey(t *testing.T) {
+	if _, err := FormatValidatorPubkey(secp256k1.GenPrivKey().PubKey()); err == nil {
+		t.Errorf("FormatValidatorPubkey() accepted a secp256k1 pubkey")
+	}
+}
+
+func TestStakeAsValidatorSender(t *testing.T) {
+	signer := NewSignerWithPrivateKey(bytes.Repeat([]byte{1}, 32))
+	other := NewSignerWithPrivateKey(bytes.Repeat([]byte{2}, 32))
+	msg := &govTokenTypes.MsgStakeAsValidator{Sender: other.CosmosAddr.String()}
+
+	if _, _, err := (&Server{}).StakeAsValidator(*signer, msg); err == nil {
+		t.Errorf("StakeAsValidator() accepted a sender that is not the signer")
+	}
+	if msg.Sender != other.CosmosAddr.String() {
+		t.Errorf("StakeAsValidator() changed the sender to %v", msg.Sender)
+	}
+}
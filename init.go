@@ -1,11 +1,36 @@
 package gosdk
 
 import (
+	"github.com/hack2fun/gosdk/crypto/ethsecp256k1"
+	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/std"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
 )
 
 func init() {
 	conf := sdk.GetConfig()
 	SetBech32Prefixes(conf)
 	SetBip44CoinType(conf)
+	registerInterfaces(interfaceRegistry)
+}
+
+// registerInterfaces registers the account, public key and proposal types that are decoded from chain responses.
+func registerInterfaces(registry codecTypes.InterfaceRegistry) {
+	std.RegisterInterfaces(registry)
+	authTypes.RegisterInterfaces(registry)
+	vestingtypes.RegisterInterfaces(registry)
+	govv1.RegisterInterfaces(registry)
+	govv1beta1.RegisterInterfaces(registry)
+	paramproposal.RegisterInterfaces(registry)
+	cysicTypes.RegisterInterfaces(registry)
+	registry.RegisterImplementations((*cryptotypes.PubKey)(nil), &ethsecp256k1.PubKey{})
+	registry.RegisterImplementations((*cryptotypes.PrivKey)(nil), &ethsecp256k1.PrivKey{})
 }
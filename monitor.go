@@ -0,0 +1,333 @@
+package gosdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// Validator alert types
+const (
+	AlertMissedBlocks      = "missed_blocks"
+	AlertJailed            = "jailed"
+	AlertTombstoned        = "tombstoned"
+	AlertCommissionChanged = "commission_changed"
+	AlertNotBonded         = "not_bonded"
+)
+
+const (
+	defaultMonitorPollInterval = 6 * BlockTime
+	defaultMonitorDedupWindow  = 6 * time.Hour
+	defaultWebhookTimeout      = 10 * time.Second
+)
+
+// ValidatorAlert is a health problem of a validator some monitored delegators are staked with.
+type ValidatorAlert struct {
+	Type       string    `json:"type"`
+	Validator  string    `json:"validator"`
+	Moniker    string    `json:"moniker"`
+	Delegators []string  `json:"delegators"`
+	Message    string    `json:"message"`
+	Time       time.Time `json:"time"`
+}
+
+// AlertSink receives the alerts raised by a ValidatorMonitor.
+type AlertSink interface {
+	SendAlert(alert ValidatorAlert) error
+}
+
+// AlertSinkFunc adapts a callback to an AlertSink.
+type AlertSinkFunc func(alert ValidatorAlert) error
+
+// SendAlert calls f with the alert.
+func (f AlertSinkFunc) SendAlert(alert ValidatorAlert) error {
+	return f(alert)
+}
+
+// LogAlertSink is an AlertSink writing alerts to the standard logger.
+type LogAlertSink struct{}
+
+// SendAlert logs the alert.
+func (LogAlertSink) SendAlert(alert ValidatorAlert) error {
+	log.Printf("validator alert, type: %v, validator: %v (%v), delegators: %v, %v",
+		alert.Type, alert.Validator, alert.Moniker, alert.Delegators, alert.Message)
+	return nil
+}
+
+// WebhookAlertSink is an AlertSink posting alerts as JSON to a URL.
+type WebhookAlertSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookAlertSink creates a new WebhookAlertSink.
+//
+// @param url the URL the alerts are posted to
+// @return a new WebhookAlertSink instance
+func NewWebhookAlertSink(url string) *WebhookAlertSink {
+	return &WebhookAlertSink{
+		URL:    url,
+		Client: &http.Client{Timeout: defaultWebhookTimeout},
+	}
+}
+
+// SendAlert posts the alert to the webhook URL.
+//
+// @param alert the alert
+// @return an error if the request fails or the response status is not 2xx
+func (w *WebhookAlertSink) SendAlert(alert ValidatorAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %v responded with status: %v", w.URL, resp.Status)
+	}
+
+	return nil
+}
+
+// ValidatorMonitorConfig configures a ValidatorMonitor.
+type ValidatorMonitorConfig struct {
+	// Delegators are the 0x or cysic addresses whose validators are monitored
+	Delegators []string
+	// PollInterval is how often the validators are checked
+	PollInterval time.Duration
+	// MissedBlocksThreshold is the missed blocks counter from which an alert is raised, 0 disables the alert
+	MissedBlocksThreshold int64
+	// CommissionChangeThreshold is the minimum absolute commission rate change raising an alert, nil alerts on any change
+	CommissionChangeThreshold *sdk.Dec
+	// DedupWindow is how long an alert for an unresolved problem is suppressed before being raised again
+	DedupWindow time.Duration
+	// Sinks receive the alerts
+	Sinks []AlertSink
+}
+
+// ValidatorMonitor watches the health of the validators a set of delegators is staked with.
+//
+// Jailing, tombstoning, missed blocks above the threshold and leaving the bonded set are raised
+// while they last, at most once per DedupWindow, and raised again as soon as they reoccur after
+// being resolved. Commission changes are raised once per change.
+type ValidatorMonitor struct {
+	server *Server
+	config ValidatorMonitorConfig
+	// commissions holds the last commission rate of each validator that was alerted on, or seen first
+	commissions map[string]sdk.Dec
+	raised      map[string]time.Time
+}
+
+// NewValidatorMonitor creates a new ValidatorMonitor.
+//
+// @param config the monitor configuration
+// @return a new ValidatorMonitor instance, or an error if a delegator address is invalid
+func (s *Server) NewValidatorMonitor(config ValidatorMonitorConfig) (*ValidatorMonitor, error) {
+	delegators := make([]string, 0, len(config.Delegators))
+	for _, delegator := range config.Delegators {
+		cosmosAddr, err := ConvertToCysicAddress(delegator)
+		if err != nil {
+			log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegator, err.Error())
+			return nil, err
+		}
+		delegators = append(delegators, cosmosAddr)
+	}
+	config.Delegators = delegators
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultMonitorPollInterval
+	}
+	if config.DedupWindow <= 0 {
+		config.DedupWindow = defaultMonitorDedupWindow
+	}
+	if len(config.Sinks) == 0 {
+		config.Sinks = []AlertSink{LogAlertSink{}}
+	}
+
+	return &ValidatorMonitor{
+		server:      s,
+		config:      config,
+		commissions: make(map[string]sdk.Dec),
+		raised:      make(map[string]time.Time),
+	}, nil
+}
+
+// Run checks the validators every PollInterval until ctx is cancelled.
+//
+// @param ctx the context controlling the monitor's lifetime
+// @return the context error once the monitor stops
+func (m *ValidatorMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.RunOnce(); err != nil {
+			log.Printf("error when check validator health, err: %v", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce checks the validators of the delegators once and sends the new alerts to the sinks.
+//
+// The validator set, the slashing params and the signing infos are each queried once per check. When
+// a delegator or a validator can't be checked the others still are, and the failures are returned
+// together with the alerts.
+//
+// @return the alerts raised, and an error if a query fails
+func (m *ValidatorMonitor) RunOnce() ([]ValidatorAlert, error) {
+	errList := make([]error, 0)
+	delegatorsOf := make(map[string][]string)
+	for _, delegator := range m.config.Delegators {
+		validators, err := m.server.QueryDelegatorValidators(delegator)
+		if err != nil {
+			errList = append(errList, fmt.Errorf("could not query validators of %v: %w", delegator, err))
+			continue
+		}
+		for _, validator := range validators {
+			delegatorsOf[validator] = append(delegatorsOf[validator], delegator)
+		}
+	}
+
+	validatorList, err := m.server.GetAllValidators()
+	if err != nil {
+		return nil, err
+	}
+	params, err := m.server.GetSlashingParams()
+	if err != nil {
+		return nil, err
+	}
+	signingInfos, err := m.server.GetSigningInfos()
+	if err != nil {
+		return nil, err
+	}
+	validators := make(map[string]stakingtypes.Validator, len(validatorList))
+	for _, validator := range validatorList {
+		validators[validator.OperatorAddress] = validator
+	}
+
+	now := time.Now()
+	alerts := make([]ValidatorAlert, 0)
+	active := make(map[string]bool)
+	for _, validatorAddress := range sortedKeys(delegatorsOf) {
+		validator, ok := validators[validatorAddress]
+		if !ok {
+			errList = append(errList, fmt.Errorf("validator %v not found", validatorAddress))
+			continue
+		}
+		info, err := validatorInfoOf(validator, math.ZeroInt(), params.SignedBlocksWindow)
+		if err != nil {
+			errList = append(errList, fmt.Errorf("could not check validator %v: %w", validatorAddress, err))
+			continue
+		}
+		if signingInfo, ok := signingInfos[info.ConsensusAddress]; ok {
+			setSigningInfo(info, signingInfo)
+		}
+
+		for _, alert := range m.check(info, now) {
+			alert.Delegators = delegatorsOf[validatorAddress]
+			key := alertKey(alert)
+			active[key] = true
+			if raisedAt, ok := m.raised[key]; ok && now.Sub(raisedAt) < m.config.DedupWindow {
+				continue
+			}
+			m.raised[key] = now
+			alerts = append(alerts, alert)
+		}
+	}
+
+	// resolved problems are forgotten, so they are raised again when they reoccur, only a complete
+	// check tells what is resolved
+	if len(errList) == 0 {
+		for key := range m.raised {
+			if !active[key] {
+				delete(m.raised, key)
+			}
+		}
+		for validatorAddress := range m.commissions {
+			if _, ok := delegatorsOf[validatorAddress]; !ok {
+				delete(m.commissions, validatorAddress)
+			}
+		}
+	}
+
+	for _, alert := range alerts {
+		for _, sink := range m.config.Sinks {
+			if err := sink.SendAlert(alert); err != nil {
+				log.Printf("error when send %v alert of %v, err: %v", alert.Type, alert.Validator, err.Error())
+			}
+		}
+	}
+
+	return alerts, errors.Join(errList...)
+}
+
+// check lists the problems of a validator.
+func (m *ValidatorMonitor) check(info *ValidatorInfo, now time.Time) []ValidatorAlert {
+	newAlert := func(alertType string, format string, args ...interface{}) ValidatorAlert {
+		return ValidatorAlert{
+			Type:      alertType,
+			Validator: info.OperatorAddress,
+			Moniker:   info.Moniker,
+			Message:   fmt.Sprintf(format, args...),
+			Time:      now,
+		}
+	}
+
+	alerts := make([]ValidatorAlert, 0)
+	if info.Tombstoned {
+		alerts = append(alerts, newAlert(AlertTombstoned, "validator is tombstoned"))
+	} else if info.Jailed {
+		alerts = append(alerts, newAlert(AlertJailed, "validator is jailed until %v", info.JailedUntil.UTC()))
+	}
+	if m.config.MissedBlocksThreshold > 0 && info.MissedBlocks >= m.config.MissedBlocksThreshold {
+		alerts = append(alerts, newAlert(AlertMissedBlocks, "validator missed %v of the last %v blocks",
+			info.MissedBlocks, info.SignedBlocksWindow))
+	}
+	if info.Status != stakingtypes.Bonded.String() && !info.Jailed {
+		alerts = append(alerts, newAlert(AlertNotBonded, "validator status is %v", info.Status))
+	}
+
+	// small changes are compared against the last alerted rate, so they can't creep past the threshold unnoticed
+	previousRate, ok := m.commissions[info.OperatorAddress]
+	if !ok {
+		m.commissions[info.OperatorAddress] = info.CommissionRate
+	} else if !info.CommissionRate.Equal(previousRate) {
+		change := info.CommissionRate.Sub(previousRate).Abs()
+		if m.config.CommissionChangeThreshold == nil || change.GTE(*m.config.CommissionChangeThreshold) {
+			alerts = append(alerts, newAlert(AlertCommissionChanged, "commission rate changed from %v to %v",
+				previousRate, info.CommissionRate))
+			m.commissions[info.OperatorAddress] = info.CommissionRate
+		}
+	}
+
+	return alerts
+}
+
+// alertKey identifies an alert for de-duplication, commission changes are keyed by the message so each change is raised.
+func alertKey(alert ValidatorAlert) string {
+	if alert.Type == AlertCommissionChanged {
+		return alert.Type + "/" + alert.Validator + "/" + alert.Message
+	}
+
+	return alert.Type + "/" + alert.Validator
+}
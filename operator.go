@@ -0,0 +1,293 @@
+package gosdk
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cosmossdk.io/math"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	tmed25519 "github.com/tendermint/tendermint/crypto/ed25519"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/privval"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CommissionChangeInterval is the minimum time between two commission rate changes of a validator.
+const CommissionChangeInterval = 24 * time.Hour
+
+// CreateValidatorParams describes a new validator.
+type CreateValidatorParams struct {
+	ConsensusPubKey         cryptotypes.PubKey
+	Moniker                 string
+	Identity                string
+	Website                 string
+	SecurityContact         string
+	Details                 string
+	CommissionRate          sdk.Dec
+	CommissionMaxRate       sdk.Dec
+	CommissionMaxChangeRate sdk.Dec
+	MinSelfDelegation       math.Int
+	// SelfDelegation is the CGT, in base units, delegated by the operator on creation
+	SelfDelegation math.Int
+}
+
+// EditValidatorParams describes the changes to a validator, nil fields are left unchanged.
+type EditValidatorParams struct {
+	Moniker           *string
+	Identity          *string
+	Website           *string
+	SecurityContact   *string
+	Details           *string
+	CommissionRate    *sdk.Dec
+	MinSelfDelegation *math.Int
+}
+
+// GenerateConsensusKey generates an ed25519 consensus key and writes it as a Tendermint priv_validator_key.json
+// with an empty priv_validator_state.json, ready to be used by a node.
+//
+// @param keyFilePath the path of the key file, it must not exist
+// @param stateFilePath the path of the state file, it is only written if it does not exist
+// @return the consensus pubkey, or an error if the files can't be written
+func GenerateConsensusKey(keyFilePath string, stateFilePath string) (cryptotypes.PubKey, error) {
+	if _, err := os.Stat(keyFilePath); err == nil {
+		return nil, fmt.Errorf("key file %v already exists", keyFilePath)
+	}
+
+	privKey := tmed25519.GenPrivKey()
+	keyBytes, err := tmjson.MarshalIndent(privval.FilePVKey{
+		Address: privKey.PubKey().Address(),
+		PubKey:  privKey.PubKey(),
+		PrivKey: privKey,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFilePath, keyBytes, 0o600); err != nil {
+		log.Printf("error when write key file: %v, err: %v", keyFilePath, err.Error())
+		return nil, err
+	}
+
+	if _, err := os.Stat(stateFilePath); errors.Is(err, os.ErrNotExist) {
+		stateBytes, err := tmjson.MarshalIndent(privval.FilePVLastSignState{}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(stateFilePath, stateBytes, 0o600); err != nil {
+			log.Printf("error when write state file: %v, err: %v", stateFilePath, err.Error())
+			return nil, err
+		}
+	}
+
+	return cryptocodec.FromTmPubKeyInterface(privKey.PubKey())
+}
+
+// LoadConsensusPubKey reads the consensus pubkey from a Tendermint priv_validator_key.json.
+//
+// @param keyFilePath the path of the key file
+// @return the consensus pubkey, or an error if the file can't be read
+func LoadConsensusPubKey(keyFilePath string) (cryptotypes.PubKey, error) {
+	keyBytes, err := os.ReadFile(keyFilePath)
+	if err != nil {
+		log.Printf("error when read key file: %v, err: %v", keyFilePath, err.Error())
+		return nil, err
+	}
+
+	var key privval.FilePVKey
+	if err := tmjson.Unmarshal(keyBytes, &key); err != nil {
+		log.Printf("error when unmarshal key file: %v, err: %v", keyFilePath, err.Error())
+		return nil, err
+	}
+	if key.PubKey == nil {
+		return nil, fmt.Errorf("key file %v has no pub_key", keyFilePath)
+	}
+
+	return cryptocodec.FromTmPubKeyInterface(key.PubKey)
+}
+
+// GetValidatorAddress derives the cysicvaloper operator address of a signer.
+//
+// @param signer the Signer instance of the operator
+// @return the operator address
+func GetValidatorAddress(signer Signer) string {
+	return sdk.ValAddress(signer.CosmosAddr).String()
+}
+
+// ConvertToValidatorAddress converts a 0x or cysic account address to its cysicvaloper operator address.
+//
+// @param addr the account address
+// @return the operator address, or an error if the address is invalid
+func ConvertToValidatorAddress(addr string) (string, error) {
+	accAddr, err := toAccAddress(addr)
+	if err != nil {
+		return "", err
+	}
+
+	return sdk.ValAddress(accAddr).String(), nil
+}
+
+// PreflightCreateValidator checks a new validator can be created by the signer.
+//
+// @param signer the Signer instance of the operator
+// @param params the new validator
+// @return an error if the signer already operates a validator or the consensus key is already used
+func (s *Server) PreflightCreateValidator(signer Signer, params CreateValidatorParams) error {
+	if params.ConsensusPubKey == nil {
+		return fmt.Errorf("consensus pubkey is required")
+	}
+
+	validatorAddress := GetValidatorAddress(signer)
+	if _, err := s.GetValidator(validatorAddress); err == nil {
+		return fmt.Errorf("validator %v already exists", validatorAddress)
+	} else if status.Code(err) != codes.NotFound {
+		return err
+	}
+
+	consAddr := sdk.ConsAddress(params.ConsensusPubKey.Address())
+	return s.IterateValidators("", func(validator stakingtypes.Validator) error {
+		validatorConsAddr, err := GetValidatorConsAddress(validator)
+		if err != nil {
+			return err
+		}
+		if validatorConsAddr.Equals(consAddr) {
+			return fmt.Errorf("consensus key %v is already used by validator %v", consAddr.String(), validator.OperatorAddress)
+		}
+		return nil
+	})
+}
+
+// CreateValidator creates a validator operated by the signer, with an initial CGT self-delegation.
+//
+// @param signer the Signer instance of the operator
+// @param params the new validator
+// @return the transaction hash as a string, or an error if the preflight or the creation fails
+func (s *Server) CreateValidator(signer Signer, params CreateValidatorParams) (string, error) {
+	if err := s.PreflightCreateValidator(signer, params); err != nil {
+		return "", err
+	}
+
+	description := stakingtypes.NewDescription(params.Moniker, params.Identity, params.Website, params.SecurityContact, params.Details)
+	commission := stakingtypes.NewCommissionRates(params.CommissionRate, params.CommissionMaxRate, params.CommissionMaxChangeRate)
+
+	msg, err := stakingtypes.NewMsgCreateValidator(
+		sdk.ValAddress(signer.CosmosAddr),
+		params.ConsensusPubKey,
+		sdk.NewCoin(CGTToken, params.SelfDelegation),
+		description,
+		commission,
+		params.MinSelfDelegation,
+	)
+	if err != nil {
+		log.Printf("error when new create validator msg, err: %v", err.Error())
+		return "", err
+	}
+
+	return s.broadcastMsg(signer, msg)
+}
+
+// PreflightEditValidator checks the changes to the validator of the signer are accepted by the chain.
+//
+// @param signer the Signer instance of the operator
+// @param params the changes to the validator
+// @param now the time the change is expected to be executed at
+// @return an error if the commission rate exceeds the max rate or max change rate, was changed within
+// the last 24 hours, or the min self delegation decreases or exceeds the validator tokens
+func (s *Server) PreflightEditValidator(signer Signer, params EditValidatorParams, now time.Time) error {
+	validator, err := s.GetValidator(GetValidatorAddress(signer))
+	if err != nil {
+		return err
+	}
+
+	if params.CommissionRate != nil {
+		newRate := *params.CommissionRate
+		commission := validator.Commission
+		if newRate.IsNegative() {
+			return fmt.Errorf("commission rate can't be negative: %v", newRate)
+		}
+		if newRate.GT(commission.MaxRate) {
+			return fmt.Errorf("commission rate %v exceeds max rate %v", newRate, commission.MaxRate)
+		}
+		if newRate.Sub(commission.Rate).Abs().GT(commission.MaxChangeRate) {
+			return fmt.Errorf("commission rate change from %v to %v exceeds max change rate %v", commission.Rate, newRate, commission.MaxChangeRate)
+		}
+		if next := commission.UpdateTime.Add(CommissionChangeInterval); now.Before(next) {
+			return fmt.Errorf("commission rate can't be changed before %v", next.UTC())
+		}
+	}
+
+	if params.MinSelfDelegation != nil {
+		newMin := *params.MinSelfDelegation
+		if !newMin.GT(validator.MinSelfDelegation) {
+			return fmt.Errorf("min self delegation can only increase, current: %v, new: %v", validator.MinSelfDelegation, newMin)
+		}
+		if newMin.GT(validator.Tokens) {
+			return fmt.Errorf("min self delegation %v exceeds validator tokens %v", newMin, validator.Tokens)
+		}
+	}
+
+	return nil
+}
+
+// EditValidator edits the description, commission rate or min self delegation of the validator of the signer.
+//
+// @param signer the Signer instance of the operator
+// @param params the changes to the validator
+// @return the transaction hash as a string, or an error if the preflight or the edit fails
+func (s *Server) EditValidator(signer Signer, params EditValidatorParams) (string, error) {
+	if err := s.PreflightEditValidator(signer, params, time.Now()); err != nil {
+		return "", err
+	}
+
+	description := stakingtypes.NewDescription(
+		valueOrDoNotModify(params.Moniker),
+		valueOrDoNotModify(params.Identity),
+		valueOrDoNotModify(params.Website),
+		valueOrDoNotModify(params.SecurityContact),
+		valueOrDoNotModify(params.Details),
+	)
+	msg := stakingtypes.NewMsgEditValidator(sdk.ValAddress(signer.CosmosAddr), description, params.CommissionRate, params.MinSelfDelegation)
+
+	return s.broadcastMsg(signer, msg)
+}
+
+// Unjail unjails the validator of the signer.
+//
+// @param signer the Signer instance of the operator
+// @return the transaction hash as a string, or an error if the validator can't be unjailed yet or the unjail fails
+func (s *Server) Unjail(signer Signer) (string, error) {
+	validatorAddress := GetValidatorAddress(signer)
+	info, err := s.GetValidatorInfo(validatorAddress)
+	if err != nil {
+		return "", err
+	}
+	if !info.Jailed {
+		return "", fmt.Errorf("validator %v is not jailed", validatorAddress)
+	}
+	if info.Tombstoned {
+		return "", fmt.Errorf("validator %v is tombstoned and can't be unjailed", validatorAddress)
+	}
+	if time.Now().Before(info.JailedUntil) {
+		return "", fmt.Errorf("validator %v is jailed until %v", validatorAddress, info.JailedUntil.UTC())
+	}
+	if info.SelfDelegation.LT(info.MinSelfDelegation) {
+		return "", fmt.Errorf("self delegation %v is below min self delegation %v", info.SelfDelegation, info.MinSelfDelegation)
+	}
+
+	msg := slashingtypes.NewMsgUnjail(sdk.ValAddress(signer.CosmosAddr))
+	return s.broadcastMsg(signer, msg)
+}
+
+func valueOrDoNotModify(value *string) string {
+	if value == nil {
+		return stakingtypes.DoNotModifyDesc
+	}
+
+	return *value
+}
@@ -0,0 +1,654 @@
+package gosdk
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// Payout chunk status
+const (
+	PayoutChunkPending = "pending"
+	PayoutChunkLanded  = "landed"
+	PayoutChunkFailed  = "failed"
+)
+
+// Payout entry status in the reconciliation report
+const (
+	PayoutEntryPaid   = "paid"
+	PayoutEntryFailed = "failed"
+	PayoutEntryUnpaid = "unpaid"
+)
+
+// ErrPayoutChunkUnresolved is returned by RunPayout when a broadcast chunk can't be found but its sequence has
+// been used, e.g. it landed on a node that doesn't index txs. The payout stops until the chunk is reconciled by hand.
+var ErrPayoutChunkUnresolved = errors.New("payout chunk unresolved")
+
+const (
+	defaultPayoutOutputsPerChunk = 500
+	defaultPayoutTxTimeout       = 6 * BlockTime
+	maxPayoutSequenceRetry       = 3
+)
+
+// PayoutEntry is a single payment of a payout list, the amount is in base units.
+type PayoutEntry struct {
+	Address string      `json:"address"`
+	Denom   string      `json:"denom"`
+	Amount  sdkmath.Int `json:"amount"`
+}
+
+// PayoutChunk is a MsgMultiSend transaction paying the entries in [Start, End).
+type PayoutChunk struct {
+	Index    int    `json:"index"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	GasLimit uint64 `json:"gas_limit"`
+	Sequence uint64 `json:"sequence"`
+	TxHash   string `json:"tx_hash"`
+	Height   int64  `json:"height"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PayoutJournal records which chunks of a payout have been broadcast and landed, so a payout can be resumed.
+type PayoutJournal struct {
+	PayoutID string        `json:"payout_id"`
+	Sender   string        `json:"sender"`
+	Chunks   []PayoutChunk `json:"chunks"`
+}
+
+// PayoutConfig configures RunPayout.
+type PayoutConfig struct {
+	// JournalPath is the file the journal is kept in, required
+	JournalPath string
+	// ReportPath is the file the reconciliation report is written to, JSON or CSV by extension, optional
+	ReportPath string
+	// MaxOutputsPerChunk is the maximum number of outputs per MsgMultiSend
+	MaxOutputsPerChunk int
+	// GasAdjustment is multiplied with the simulated gas to get the gas limit of a chunk
+	GasAdjustment float64
+	// TxTimeout is how long to wait for a chunk to be packed
+	TxTimeout time.Duration
+}
+
+// PayoutEntryResult is the reconciliation result of one payout entry.
+type PayoutEntryResult struct {
+	Index int `json:"index"`
+	PayoutEntry
+	Status string `json:"status"`
+	TxHash string `json:"tx_hash,omitempty"`
+	Height int64  `json:"height,omitempty"`
+}
+
+// PayoutReport is the reconciliation report of a payout.
+type PayoutReport struct {
+	PayoutID     string              `json:"payout_id"`
+	Sender       string              `json:"sender"`
+	TotalEntries int                 `json:"total_entries"`
+	PaidEntries  int                 `json:"paid_entries"`
+	Planned      sdk.Coins           `json:"planned"`
+	Paid         sdk.Coins           `json:"paid"`
+	Unpaid       sdk.Coins           `json:"unpaid"`
+	Chunks       []PayoutChunk       `json:"chunks"`
+	Entries      []PayoutEntryResult `json:"entries"`
+}
+
+// DefaultPayoutConfig returns a PayoutConfig with default chunking and timeout settings.
+//
+// @param journalPath the file the journal is kept in
+// @param reportPath the file the reconciliation report is written to
+// @return the default PayoutConfig
+func DefaultPayoutConfig(journalPath string, reportPath string) PayoutConfig {
+	return PayoutConfig{
+		JournalPath:        journalPath,
+		ReportPath:         reportPath,
+		MaxOutputsPerChunk: defaultPayoutOutputsPerChunk,
+		GasAdjustment:      defaultGasAdjustment,
+		TxTimeout:          defaultPayoutTxTimeout,
+	}
+}
+
+// LoadPayoutEntries loads a payout list from a .csv or .json file.
+//
+// @param path the path of the payout list
+// @return the payout entries, or an error if the file cannot be read or parsed
+func LoadPayoutEntries(path string) ([]PayoutEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("error when open payout file: %v, err: %v", path, err.Error())
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParsePayoutCSV(file)
+	case ".json":
+		return ParsePayoutJSON(file)
+	default:
+		return nil, fmt.Errorf("unsupported payout file: %v, expected .csv or .json", path)
+	}
+}
+
+// ParsePayoutCSV parses a payout list with address,denom,amount rows, an optional header row is skipped.
+//
+// @param reader the CSV content
+// @return the payout entries, or an error if a row is malformed
+func ParsePayoutCSV(reader io.Reader) ([]PayoutEntry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+
+	result := make([]PayoutEntry, 0)
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid payout csv, err: %v", err)
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+			continue
+		}
+
+		amount, ok := sdkmath.NewIntFromString(strings.TrimSpace(record[2]))
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %v at line %v", record[2], line)
+		}
+		result = append(result, PayoutEntry{
+			Address: strings.TrimSpace(record[0]),
+			Denom:   strings.TrimSpace(record[1]),
+			Amount:  amount,
+		})
+	}
+
+	return result, nil
+}
+
+// ParsePayoutJSON parses a payout list given as a JSON array of {"address", "denom", "amount"} objects.
+//
+// @param reader the JSON content
+// @return the payout entries, or an error if an item is malformed
+func ParsePayoutJSON(reader io.Reader) ([]PayoutEntry, error) {
+	var items []struct {
+		Address string      `json:"address"`
+		Denom   string      `json:"denom"`
+		Amount  json.Number `json:"amount"`
+	}
+	if err := json.NewDecoder(reader).Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid payout json, err: %v", err)
+	}
+
+	result := make([]PayoutEntry, 0, len(items))
+	for i, item := range items {
+		amount, ok := sdkmath.NewIntFromString(item.Amount.String())
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %v at item %v", item.Amount, i)
+		}
+		result = append(result, PayoutEntry{
+			Address: item.Address,
+			Denom:   item.Denom,
+			Amount:  amount,
+		})
+	}
+
+	return result, nil
+}
+
+// ValidatePayoutEntries checks every entry of a payout list and converts the addresses to cysic addresses.
+//
+// @param entries the payout entries
+// @return the normalized entries, or an error listing every invalid entry
+func ValidatePayoutEntries(entries []PayoutEntry) ([]PayoutEntry, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("payout list is empty")
+	}
+
+	result := make([]PayoutEntry, 0, len(entries))
+	var errList []string
+	for i, entry := range entries {
+		cysicAddr, err := ConvertToCysicAddress(entry.Address)
+		if err == nil {
+			_, err = sdk.AccAddressFromBech32(cysicAddr)
+		}
+		if err != nil {
+			errList = append(errList, fmt.Sprintf("entry %v: invalid address %v: %v", i, entry.Address, err))
+		}
+		if err := sdk.ValidateDenom(entry.Denom); err != nil {
+			errList = append(errList, fmt.Sprintf("entry %v: %v", i, err))
+		}
+		if entry.Amount.IsNil() || !entry.Amount.IsPositive() {
+			errList = append(errList, fmt.Sprintf("entry %v: amount must be positive", i))
+		}
+
+		result = append(result, PayoutEntry{
+			Address: cysicAddr,
+			Denom:   entry.Denom,
+			Amount:  entry.Amount,
+		})
+	}
+
+	if len(errList) > 0 {
+		return nil, fmt.Errorf("invalid payout list:\n%v", strings.Join(errList, "\n"))
+	}
+
+	return result, nil
+}
+
+// RunPayout pays a payout list with MsgMultiSend transactions split into gas-bounded chunks.
+//
+// Chunks are sized by simulation so that the adjusted gas stays within the server's gas limit,
+// then broadcast one by one, each waiting to be packed before the next is signed. Every chunk is
+// recorded in the journal before it is broadcast, so calling RunPayout again with the same list
+// and journal resumes after the last landed chunk. A chunk that was broadcast but can't be found
+// stays pending and is only signed again with its own journaled sequence, so at most one version
+// of it can land. If the account sequence has moved past it, RunPayout stops with
+// ErrPayoutChunkUnresolved instead of paying its entries a second time.
+//
+// @param signer the Signer instance used to sign the transactions
+// @param entries the payout entries
+// @param config the payout configuration
+// @return the reconciliation report, or an error if the payout stopped before every entry was paid
+func (s *Server) RunPayout(signer Signer, entries []PayoutEntry, config PayoutConfig) (*PayoutReport, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	if config.JournalPath == "" {
+		return nil, fmt.Errorf("payout journal path can't be empty")
+	}
+	if config.MaxOutputsPerChunk <= 0 {
+		config.MaxOutputsPerChunk = defaultPayoutOutputsPerChunk
+	}
+	if config.GasAdjustment <= 0 {
+		config.GasAdjustment = defaultGasAdjustment
+	}
+	if config.TxTimeout <= 0 {
+		config.TxTimeout = defaultPayoutTxTimeout
+	}
+
+	entries, err := ValidatePayoutEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := loadPayoutJournal(config.JournalPath, payoutID(signer.CosmosAddr.String(), entries), signer.CosmosAddr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	runErr := s.runPayoutChunks(signer, entries, journal, config)
+
+	report := buildPayoutReport(journal, entries)
+	if config.ReportPath != "" {
+		if err := writePayoutReport(config.ReportPath, report); err != nil {
+			log.Printf("error when write payout report: %v, err: %v", config.ReportPath, err.Error())
+			if runErr == nil {
+				runErr = err
+			}
+		}
+	}
+
+	return report, runErr
+}
+
+func (s *Server) runPayoutChunks(signer Signer, entries []PayoutEntry, journal *PayoutJournal, config PayoutConfig) error {
+	if err := s.resolvePendingPayoutChunk(journal, config); err != nil {
+		return err
+	}
+
+	next := payoutLandedEnd(journal)
+
+	exist, accNumber, sequence, err := s.getAccountNumberAndSequenceOnChain(signer.CosmosAddr)
+	if err != nil {
+		log.Printf("error when get accInfo on chain, addr: %v, err: %v", signer.CosmosAddr.String(), err.Error())
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("account %v not exist", signer.CosmosAddr.String())
+	}
+
+	size := config.MaxOutputsPerChunk
+	retry := 0
+	for next < len(entries) {
+		current := pendingPayoutChunk(journal)
+		resend := current != nil
+
+		var txBytes []byte
+		if resend {
+			// the pending tx may still land, so its entries are only signed again with its own sequence
+			if current.Sequence != sequence {
+				return fmt.Errorf("%w: chunk %v, tx: %v, signed with sequence %v, account sequence is %v",
+					ErrPayoutChunkUnresolved, current.Index, current.TxHash, current.Sequence, sequence)
+			}
+
+			msg, err := buildPayoutMsg(signer.CosmosAddr, entries[current.Start:current.End])
+			if err != nil {
+				return err
+			}
+			txBytes, err = s.signTx(signer, accNumber, current.Sequence, current.GasLimit, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+			current.TxHash = fmt.Sprintf("%X", tmhash.Sum(txBytes))
+			log.Printf("payout chunk %v [%v, %v) still pending, resend with sequence %v", current.Index, current.Start, current.End, current.Sequence)
+		} else {
+			end := next + size
+			if end > len(entries) {
+				end = len(entries)
+			}
+
+			msg, err := buildPayoutMsg(signer.CosmosAddr, entries[next:end])
+			if err != nil {
+				return err
+			}
+
+			signer.Nonce = sequence
+			gasUsed, err := s.SimulateTx(signer, []sdk.Msg{msg})
+			gasLimit := uint64(float64(gasUsed) * config.GasAdjustment)
+			if err != nil || gasLimit > s.GasLimit {
+				if end-next > 1 {
+					size = (end - next) / 2
+					log.Printf("payout chunk [%v, %v) too large, retry with %v outputs", next, end, size)
+					continue
+				}
+				if err != nil {
+					log.Printf("error when simulate payout chunk [%v, %v), err: %v", next, end, err.Error())
+					return err
+				}
+				return fmt.Errorf("payout entry %v needs %v gas, exceeds gas limit %v", next, gasLimit, s.GasLimit)
+			}
+
+			txBytes, err = s.signTx(signer, accNumber, sequence, gasLimit, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+
+			journal.Chunks = append(journal.Chunks, PayoutChunk{
+				Index:    len(journal.Chunks),
+				Start:    next,
+				End:      end,
+				GasLimit: gasLimit,
+				Sequence: sequence,
+				TxHash:   fmt.Sprintf("%X", tmhash.Sum(txBytes)),
+				Status:   PayoutChunkPending,
+			})
+			current = &journal.Chunks[len(journal.Chunks)-1]
+		}
+		if err := savePayoutJournal(config.JournalPath, journal); err != nil {
+			return err
+		}
+
+		resp, err := s.BroadcastTx(txBytes)
+		if err != nil {
+			log.Printf("error when broadcast payout chunk %v, err: %v", current.Index, err.Error())
+			return err
+		}
+		inMempool := resp.Codespace == sdkerrors.ErrTxInMempoolCache.Codespace() && resp.Code == sdkerrors.ErrTxInMempoolCache.ABCICode()
+		if resp.Code != 0 && resend && !inMempool {
+			// the earlier broadcast may be in a mempool with this sequence, it must not be replaced
+			return fmt.Errorf("%w: chunk %v resend rejected: %v", ErrPayoutChunkUnresolved, current.Index, resp.RawLog)
+		}
+		if resp.Code != 0 && !inMempool {
+			current.Status = PayoutChunkFailed
+			current.Error = resp.RawLog
+			if err := savePayoutJournal(config.JournalPath, journal); err != nil {
+				return err
+			}
+
+			if resp.Codespace == sdkerrors.ErrWrongSequence.Codespace() && resp.Code == sdkerrors.ErrWrongSequence.ABCICode() && retry < maxPayoutSequenceRetry {
+				retry++
+				log.Printf("payout chunk %v sequence mismatch, refresh sequence and retry", current.Index)
+				if _, _, sequence, err = s.getAccountNumberAndSequenceOnChain(signer.CosmosAddr); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("payout chunk %v rejected: %v", current.Index, resp.RawLog)
+		}
+		retry = 0
+
+		result, err := s.WaitTx(current.TxHash, config.TxTimeout)
+		if err != nil {
+			// the chunk stays pending, the next run resolves it before anything else is signed
+			log.Printf("error when wait payout chunk %v, tx: %v, err: %v", current.Index, current.TxHash, err.Error())
+			return err
+		}
+
+		current.Height = result.Height
+		sequence++
+		if result.Code != 0 {
+			current.Status = PayoutChunkFailed
+			current.Error = result.RawLog
+			if err := savePayoutJournal(config.JournalPath, journal); err != nil {
+				return err
+			}
+			return fmt.Errorf("payout chunk %v failed: %v", current.Index, result.RawLog)
+		}
+
+		current.Status = PayoutChunkLanded
+		if err := savePayoutJournal(config.JournalPath, journal); err != nil {
+			return err
+		}
+		log.Printf("payout chunk %v [%v, %v) landed, tx: %v, height: %v", current.Index, current.Start, current.End, current.TxHash, current.Height)
+
+		next = current.End
+	}
+
+	return nil
+}
+
+// resolvePendingPayoutChunk settles the status of a chunk that was broadcast by an interrupted run.
+// A chunk whose tx can't be found stays pending, so it is never sent again before its outcome is known.
+func (s *Server) resolvePendingPayoutChunk(journal *PayoutJournal, config PayoutConfig) error {
+	chunk := pendingPayoutChunk(journal)
+	if chunk == nil {
+		return nil
+	}
+
+	result, err := s.WaitTx(chunk.TxHash, config.TxTimeout)
+	if errors.Is(err, ErrTxNotPacked) {
+		log.Printf("pending payout chunk %v not found, tx: %v, err: %v", chunk.Index, chunk.TxHash, err.Error())
+		return nil
+	}
+	if err != nil {
+		log.Printf("error when resolve pending payout chunk %v, tx: %v, err: %v", chunk.Index, chunk.TxHash, err.Error())
+		return err
+	}
+
+	chunk.Height = result.Height
+	if result.Code == 0 {
+		chunk.Status = PayoutChunkLanded
+	} else {
+		chunk.Status = PayoutChunkFailed
+		chunk.Error = result.RawLog
+	}
+
+	return savePayoutJournal(config.JournalPath, journal)
+}
+
+// pendingPayoutChunk returns the chunk that was broadcast but whose outcome is unknown, nil if there is none.
+// Chunks are sent one by one, so there is at most one.
+func pendingPayoutChunk(journal *PayoutJournal) *PayoutChunk {
+	for i := range journal.Chunks {
+		if journal.Chunks[i].Status == PayoutChunkPending {
+			return &journal.Chunks[i]
+		}
+	}
+
+	return nil
+}
+
+// payoutLandedEnd returns the index of the first entry not covered by a landed chunk.
+func payoutLandedEnd(journal *PayoutJournal) int {
+	next := 0
+	for _, chunk := range journal.Chunks {
+		if chunk.Status == PayoutChunkLanded && chunk.End > next {
+			next = chunk.End
+		}
+	}
+
+	return next
+}
+
+func buildPayoutMsg(from sdk.AccAddress, entries []PayoutEntry) (sdk.Msg, error) {
+	toAddrList := make([]string, 0, len(entries))
+	coinList := make([]string, 0, len(entries))
+	amountList := make([]sdkmath.Int, 0, len(entries))
+	for _, entry := range entries {
+		toAddrList = append(toAddrList, entry.Address)
+		coinList = append(coinList, entry.Denom)
+		amountList = append(amountList, entry.Amount)
+	}
+
+	return buildMultiSendMsg(from, toAddrList, coinList, amountList)
+}
+
+// payoutID identifies a payout list so a journal can't be resumed with a different list.
+func payoutID(sender string, entries []PayoutEntry) string {
+	hash := sha256.New()
+	hash.Write([]byte(sender + "\n"))
+	for _, entry := range entries {
+		hash.Write([]byte(entry.Address + "," + entry.Denom + "," + entry.Amount.String() + "\n"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func loadPayoutJournal(path string, id string, sender string) (*PayoutJournal, error) {
+	bz, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &PayoutJournal{PayoutID: id, Sender: sender, Chunks: make([]PayoutChunk, 0)}, nil
+	}
+	if err != nil {
+		log.Printf("error when read payout journal: %v, err: %v", path, err.Error())
+		return nil, err
+	}
+
+	journal := &PayoutJournal{}
+	if err := json.Unmarshal(bz, journal); err != nil {
+		return nil, fmt.Errorf("invalid payout journal %v, err: %v", path, err)
+	}
+	if journal.PayoutID != id || journal.Sender != sender {
+		return nil, fmt.Errorf("payout journal %v belongs to another payout list or sender", path)
+	}
+
+	return journal, nil
+}
+
+func savePayoutJournal(path string, journal *PayoutJournal) error {
+	bz, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write then rename, so a crash never leaves a truncated journal behind
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, bz, 0o644); err != nil {
+		log.Printf("error when write payout journal: %v, err: %v", tmpPath, err.Error())
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func buildPayoutReport(journal *PayoutJournal, entries []PayoutEntry) *PayoutReport {
+	report := &PayoutReport{
+		PayoutID:     journal.PayoutID,
+		Sender:       journal.Sender,
+		TotalEntries: len(entries),
+		Planned:      sdk.NewCoins(),
+		Paid:         sdk.NewCoins(),
+		Unpaid:       sdk.NewCoins(),
+		Chunks:       journal.Chunks,
+		Entries:      make([]PayoutEntryResult, 0, len(entries)),
+	}
+
+	for i, entry := range entries {
+		result := PayoutEntryResult{Index: i, PayoutEntry: entry, Status: PayoutEntryUnpaid}
+		for _, chunk := range journal.Chunks {
+			if i < chunk.Start || i >= chunk.End {
+				continue
+			}
+
+			if chunk.Status == PayoutChunkLanded {
+				result.Status = PayoutEntryPaid
+				result.TxHash = chunk.TxHash
+				result.Height = chunk.Height
+				break
+			}
+			if chunk.Status == PayoutChunkFailed {
+				result.Status = PayoutEntryFailed
+				result.TxHash = chunk.TxHash
+			}
+		}
+
+		coin := sdk.NewCoin(entry.Denom, entry.Amount)
+		report.Planned = report.Planned.Add(coin)
+		if result.Status == PayoutEntryPaid {
+			report.PaidEntries++
+			report.Paid = report.Paid.Add(coin)
+		} else {
+			report.Unpaid = report.Unpaid.Add(coin)
+		}
+		report.Entries = append(report.Entries, result)
+	}
+
+	return report
+}
+
+func writePayoutReport(path string, report *PayoutReport) error {
+	if strings.ToLower(filepath.Ext(path)) != ".csv" {
+		bz, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, bz, 0o644)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"index", "address", "denom", "amount", "status", "tx_hash", "height"}); err != nil {
+		return err
+	}
+	for _, entry := range report.Entries {
+		row := []string{
+			strconv.Itoa(entry.Index),
+			entry.Address,
+			entry.Denom,
+			entry.Amount.String(),
+			entry.Status,
+			entry.TxHash,
+			strconv.FormatInt(entry.Height, 10),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
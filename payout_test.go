@@ -0,0 +1,317 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	sdkClient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	authSigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeChain serves the account and tx queries RunPayout needs, every accepted tx is packed in its own block.
+type fakeChain struct {
+	authTypes.UnimplementedQueryServer
+	sdkTx.UnimplementedServiceServer
+
+	mu           sync.Mutex
+	address      sdk.AccAddress
+	sequence     uint64
+	height       int64
+	gasPerOutput uint64
+	txs          map[string]*sdk.TxResponse
+	paid         map[string]int
+	broadcasts   int
+
+	// failures broadcasts fail before they reach the chain once failAfter txs were packed
+	failAfter int
+	failures  int
+	// unindexed packs txs without indexing them, so GetTx never finds them
+	unindexed bool
+}
+
+func (c *fakeChain) Account(_ context.Context, req *authTypes.QueryAccountRequest) (*authTypes.QueryAccountResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	account, err := codecTypes.NewAnyWithValue(authTypes.NewBaseAccount(c.address, nil, 7, c.sequence))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authTypes.QueryAccountResponse{Account: account}, nil
+}
+
+func (c *fakeChain) Simulate(_ context.Context, req *sdkTx.SimulateRequest) (*sdkTx.SimulateResponse, error) {
+	msg, _, err := decodeFakeMultiSend(req.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdkTx.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: c.gasPerOutput * uint64(len(msg.Outputs))}}, nil
+}
+
+func (c *fakeChain) BroadcastTx(_ context.Context, req *sdkTx.BroadcastTxRequest) (*sdkTx.BroadcastTxResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures > 0 && c.broadcasts >= c.failAfter {
+		c.failures--
+		return nil, status.Error(codes.Unavailable, "node unavailable")
+	}
+
+	msg, sequence, err := decodeFakeMultiSend(req.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+	txHash := fmt.Sprintf("%X", tmhash.Sum(req.TxBytes))
+	if sequence != c.sequence {
+		return &sdkTx.BroadcastTxResponse{TxResponse: &sdk.TxResponse{
+			TxHash:    txHash,
+			Codespace: sdkerrors.ErrWrongSequence.Codespace(),
+			Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+			RawLog:    fmt.Sprintf("account sequence mismatch, expected %v, got %v", c.sequence, sequence),
+		}}, nil
+	}
+
+	c.broadcasts++
+	c.sequence++
+	c.height++
+	for _, output := range msg.Outputs {
+		c.paid[output.Address]++
+	}
+	if !c.unindexed {
+		c.txs[txHash] = &sdk.TxResponse{TxHash: txHash, Height: c.height}
+	}
+
+	return &sdkTx.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: txHash}}, nil
+}
+
+func (c *fakeChain) GetTx(_ context.Context, req *sdkTx.GetTxRequest) (*sdkTx.GetTxResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if resp, ok := c.txs[req.Hash]; ok {
+		return &sdkTx.GetTxResponse{TxResponse: resp}, nil
+	}
+
+	return nil, status.Errorf(codes.NotFound, "tx not found: %s", req.Hash)
+}
+
+// fakeChainTxConfig decodes the bank messages the SDK only ever encodes.
+var fakeChainTxConfig = func() sdkClient.TxConfig {
+	registry := codecTypes.NewInterfaceRegistry()
+	registerInterfaces(registry)
+	banktypes.RegisterInterfaces(registry)
+
+	return authTx.NewTxConfig(codec.NewProtoCodec(registry), authTx.DefaultSignModes)
+}()
+
+func decodeFakeMultiSend(txBytes []byte) (*banktypes.MsgMultiSend, uint64, error) {
+	tx, err := fakeChainTxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+	msg, ok := tx.GetMsgs()[0].(*banktypes.MsgMultiSend)
+	if !ok {
+		return nil, 0, fmt.Errorf("unexpected msg %T", tx.GetMsgs()[0])
+	}
+	sigs, err := tx.(authSigning.SigVerifiableTx).GetSignaturesV2()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return msg, sigs[0].Sequence, nil
+}
+
+// newFakeChainServer starts a gRPC server for chain on a local port and returns a Server connected to it.
+func newFakeChainServer(t *testing.T, chain *fakeChain, gasLimit uint64) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	authTypes.RegisterQueryServer(grpcServer, chain)
+	sdkTx.RegisterServiceServer(grpcServer, chain)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &Server{EndPoint: listener.Addr().String(), Conn: conn, ChainID: "cysicmint_9001-1", GasCoin: "CYS", GasPrice: 1, GasLimit: gasLimit}
+}
+
+func newPayoutTest(t *testing.T, count int, gasLimit uint64) (*Server, *fakeChain, Signer, []PayoutEntry, PayoutConfig) {
+	t.Helper()
+
+	signer := NewSignerWithPrivateKey([]byte{1, 2, 3})
+	chain := &fakeChain{
+		address:      signer.CosmosAddr,
+		sequence:     3,
+		gasPerOutput: 100,
+		txs:          make(map[string]*sdk.TxResponse),
+		paid:         make(map[string]int),
+	}
+	server := newFakeChainServer(t, chain, gasLimit)
+
+	entries := make([]PayoutEntry, 0, count)
+	for i := 0; i < count; i++ {
+		entries = append(entries, PayoutEntry{
+			Address: sdk.AccAddress([]byte(fmt.Sprintf("payout-recipient-%03d", i))).String(),
+			Denom:   "CYS",
+			Amount:  math.NewInt(int64(i + 1)),
+		})
+	}
+
+	config := DefaultPayoutConfig(filepath.Join(t.TempDir(), "journal.json"), "")
+	config.MaxOutputsPerChunk = 4
+	config.TxTimeout = time.Nanosecond
+
+	return server, chain, *signer, entries, config
+}
+
+func assertPaidOnce(t *testing.T, chain *fakeChain, entries []PayoutEntry) {
+	t.Helper()
+
+	for _, entry := range entries {
+		if paid := chain.paid[entry.Address]; paid != 1 {
+			t.Errorf("%v paid %v times, want 1", entry.Address, paid)
+		}
+	}
+}
+
+func TestRunPayoutChunks(t *testing.T) {
+	tests := []struct {
+		name       string
+		gasLimit   uint64
+		wantChunks [][2]int
+		wantErr    bool
+	}{
+		{name: "max outputs per chunk", gasLimit: 10000, wantChunks: [][2]int{{0, 4}, {4, 8}, {8, 10}}},
+		{name: "split by gas limit", gasLimit: 400, wantChunks: [][2]int{{0, 2}, {2, 4}, {4, 6}, {6, 8}, {8, 10}}},
+		{name: "single output above gas limit", gasLimit: 100, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, chain, signer, entries, config := newPayoutTest(t, 10, tt.gasLimit)
+
+			report, err := server.RunPayout(signer, entries, config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunPayout() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if chain.broadcasts != 0 {
+					t.Errorf("broadcast %v txs, want none", chain.broadcasts)
+				}
+				return
+			}
+
+			if len(report.Chunks) != len(tt.wantChunks) {
+				t.Fatalf("chunks = %+v, want %v", report.Chunks, tt.wantChunks)
+			}
+			for i, chunk := range report.Chunks {
+				if chunk.Start != tt.wantChunks[i][0] || chunk.End != tt.wantChunks[i][1] || chunk.Status != PayoutChunkLanded {
+					t.Errorf("chunk %d = %+v, want landed [%v, %v)", i, chunk, tt.wantChunks[i][0], tt.wantChunks[i][1])
+				}
+				if chunk.Sequence != uint64(3+i) {
+					t.Errorf("chunk %d sequence = %v, want %v", i, chunk.Sequence, 3+i)
+				}
+			}
+			if report.PaidEntries != len(entries) || !report.Unpaid.IsZero() {
+				t.Errorf("paid %v entries, unpaid %v, want all %v paid", report.PaidEntries, report.Unpaid, len(entries))
+			}
+			assertPaidOnce(t, chain, entries)
+		})
+	}
+}
+
+func TestRunPayoutResume(t *testing.T) {
+	server, chain, signer, entries, config := newPayoutTest(t, 10, 10000)
+
+	// the second chunk is journaled as pending but never reaches the chain
+	chain.failAfter = 1
+	chain.failures = 1
+	if _, err := server.RunPayout(signer, entries, config); err == nil {
+		t.Fatalf("RunPayout() succeeded, want the broadcast error")
+	}
+
+	journal, err := loadPayoutJournal(config.JournalPath, payoutID(signer.CosmosAddr.String(), entries), signer.CosmosAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journal.Chunks) != 2 || journal.Chunks[0].Status != PayoutChunkLanded || journal.Chunks[1].Status != PayoutChunkPending {
+		t.Fatalf("journal chunks = %+v, want one landed and one pending", journal.Chunks)
+	}
+
+	report, err := server.RunPayout(signer, entries, config)
+	if err != nil {
+		t.Fatalf("resumed RunPayout() err = %v", err)
+	}
+	if len(report.Chunks) != 3 || report.Chunks[1].Sequence != journal.Chunks[1].Sequence {
+		t.Errorf("chunks = %+v, want the pending chunk resent with sequence %v", report.Chunks, journal.Chunks[1].Sequence)
+	}
+	if report.PaidEntries != len(entries) {
+		t.Errorf("paid %v entries, want %v", report.PaidEntries, len(entries))
+	}
+	assertPaidOnce(t, chain, entries)
+
+	// a finished payout is not sent again
+	broadcasts := chain.broadcasts
+	if _, err := server.RunPayout(signer, entries, config); err != nil {
+		t.Fatalf("finished RunPayout() err = %v", err)
+	}
+	if chain.broadcasts != broadcasts {
+		t.Errorf("finished payout broadcast %v more txs", chain.broadcasts-broadcasts)
+	}
+}
+
+func TestRunPayoutUnresolvedChunk(t *testing.T) {
+	server, chain, signer, entries, config := newPayoutTest(t, 10, 10000)
+
+	// the first chunk lands, but the node doesn't index it
+	chain.unindexed = true
+	if _, err := server.RunPayout(signer, entries, config); !errors.Is(err, ErrTxNotPacked) {
+		t.Fatalf("RunPayout() err = %v, want ErrTxNotPacked", err)
+	}
+
+	report, err := server.RunPayout(signer, entries, config)
+	if !errors.Is(err, ErrPayoutChunkUnresolved) {
+		t.Fatalf("resumed RunPayout() err = %v, want ErrPayoutChunkUnresolved", err)
+	}
+	if len(report.Chunks) != 1 || report.Chunks[0].Status != PayoutChunkPending {
+		t.Errorf("chunks = %+v, want the first chunk still pending", report.Chunks)
+	}
+	if chain.broadcasts != 1 {
+		t.Errorf("broadcast %v txs, want the first chunk only", chain.broadcasts)
+	}
+	for _, entry := range entries[:4] {
+		if paid := chain.paid[entry.Address]; paid != 1 {
+			t.Errorf("%v paid %v times, want 1", entry.Address, paid)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package gosdk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	vestexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PortfolioConfig configures a portfolio report.
+type PortfolioConfig struct {
+	// Height is the block height every query reads, 0 uses the latest height
+	Height int64
+	// VeTokens are the veTokens whose binds are reported, optional
+	VeTokens []string
+	// Epoch is the epoch config of the delegate module, required with VeTokens
+	Epoch EpochConfig
+}
+
+// AccountPortfolio is everything an address owns, in base units.
+type AccountPortfolio struct {
+	Address    string    `json:"address"`
+	EthAddress string    `json:"eth_address"`
+	Liquid     sdk.Coins `json:"liquid"`
+	Delegated  sdk.Coins `json:"delegated"`
+	Unbonding  sdk.Coins `json:"unbonding"`
+	// Rewards are the pending delegation rewards, truncated to base units
+	Rewards sdk.Coins `json:"rewards"`
+	// Total is the sum of the liquid, delegated, unbonding and rewards coins
+	Total sdk.Coins `json:"total"`
+	// TotalCYS is Total valued in CYS base units, denominations other than CGT and CYS are not valued
+	TotalCYS math.Int `json:"total_cys"`
+	// Delegations are the delegated coins per validator
+	Delegations map[string]sdk.Coins `json:"delegations"`
+	// Vesting is the state of the account at the block time, when it is a vesting account
+	Vesting      *VestingInfo    `json:"vesting,omitempty"`
+	VeTokenBinds []*DelegateBind `json:"vetoken_binds,omitempty"`
+}
+
+// Portfolio is the consolidated holdings of a set of addresses at one block height.
+type Portfolio struct {
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+	// CGTToCYSRate is the govtoken exchange rate used to value CGT in CYS
+	CGTToCYSRate decimal.Decimal     `json:"cgt_to_cys_rate"`
+	Accounts     []*AccountPortfolio `json:"accounts"`
+	Total        sdk.Coins           `json:"total"`
+	TotalCYS     math.Int            `json:"total_cys"`
+}
+
+// GetPortfolio gathers the liquid, delegated, unbonding, reward, vesting and veToken holdings of addresses,
+// all read at the same block height.
+//
+// @param addresses the 0x or cysic addresses
+// @param config the report configuration
+// @return the portfolio, or an error if an address is invalid or a query fails
+func (s *Server) GetPortfolio(addresses []string, config PortfolioConfig) (*Portfolio, error) {
+	height := config.Height
+	if height <= 0 {
+		latest, err := s.GetLatestBlockHeight()
+		if err != nil {
+			return nil, err
+		}
+		height = latest
+	}
+	block, err := s.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := s.AtHeight(height)
+	rate, err := pinned.GetExchangeRate(CGTToken, CYSToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var epoch int64
+	if len(config.VeTokens) != 0 {
+		epoch, err = config.Epoch.EpochAtHeight(height)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	portfolio := &Portfolio{
+		Height:       height,
+		Time:         block.Header.Time,
+		CGTToCYSRate: rate,
+		Accounts:     make([]*AccountPortfolio, 0, len(addresses)),
+		Total:        sdk.NewCoins(),
+		TotalCYS:     math.ZeroInt(),
+	}
+	for _, addr := range addresses {
+		account, err := pinned.getAccountPortfolio(addr, portfolio.Time, config.VeTokens, epoch)
+		if err != nil {
+			return nil, err
+		}
+		account.TotalCYS = valueInCYS(account.Total, rate)
+
+		portfolio.Accounts = append(portfolio.Accounts, account)
+		portfolio.Total = portfolio.Total.Add(account.Total...)
+		portfolio.TotalCYS = portfolio.TotalCYS.Add(account.TotalCYS)
+	}
+
+	return portfolio, nil
+}
+
+func (s *Server) getAccountPortfolio(addr string, blockTime time.Time, veTokens []string, epoch int64) (*AccountPortfolio, error) {
+	cosmosAddr, err := ConvertToCysicAddress(addr)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", addr, err.Error())
+		return nil, err
+	}
+	ethAddr, err := ConvertToETHAddress(addr)
+	if err != nil {
+		log.Printf("error when convert addr: %v to ethAddr, err: %v", addr, err.Error())
+		return nil, err
+	}
+
+	result := &AccountPortfolio{
+		Address:     cosmosAddr,
+		EthAddress:  ethAddr,
+		Delegated:   sdk.NewCoins(),
+		Unbonding:   sdk.NewCoins(),
+		Delegations: make(map[string]sdk.Coins),
+	}
+
+	result.Liquid, err = s.GetBalanceList(cosmosAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	delegations, err := s.QueryDelegatorDelegations(cosmosAddr)
+	if err != nil {
+		return nil, err
+	}
+	for validatorAddress, coins := range delegations {
+		result.Delegations[validatorAddress] = sdk.NewCoins(coins...)
+		result.Delegated = result.Delegated.Add(coins...)
+	}
+
+	unbondings, err := s.QueryUnbondingDelegations(cosmosAddr)
+	if err != nil {
+		return nil, err
+	}
+	for _, unbonding := range unbondings {
+		for _, entry := range unbonding.Entries {
+			result.Unbonding = result.Unbonding.Add(sdk.NewCoin(CGTToken, entry.Balance))
+		}
+	}
+
+	_, rewards, err := s.QueryDelegateRewardDec(cosmosAddr)
+	if err != nil {
+		return nil, err
+	}
+	result.Rewards, _ = rewards.TruncateDecimal()
+
+	account, err := s.GetAccountI(cosmosAddr)
+	if err != nil && status.Code(err) != codes.NotFound {
+		log.Printf("error when GetAccountI: %v, err: %v", cosmosAddr, err.Error())
+		return nil, err
+	}
+	if vestingAccount, ok := account.(vestexported.VestingAccount); ok {
+		result.Vesting = NewVestingInfo(vestingAccount, blockTime)
+	}
+
+	for _, token := range veTokens {
+		bind, err := s.QueryDelegateBind(epoch, ethAddr, token)
+		if err != nil {
+			return nil, err
+		}
+		if bind.Validator != "" {
+			result.VeTokenBinds = append(result.VeTokenBinds, bind)
+		}
+	}
+
+	result.Total = result.Liquid.Add(result.Delegated...).Add(result.Unbonding...).Add(result.Rewards...)
+
+	return result, nil
+}
+
+// valueInCYS values CGT and CYS coins in CYS base units, truncated.
+func valueInCYS(coins sdk.Coins, cgtToCYSRate decimal.Decimal) math.Int {
+	total := coins.AmountOf(CYSToken)
+	if cgt := coins.AmountOf(CGTToken); cgt.IsPositive() {
+		value := decimal.NewFromBigInt(cgt.BigInt(), 0).Mul(cgtToCYSRate).Truncate(0)
+		total = total.Add(math.NewIntFromBigInt(value.BigInt()))
+	}
+
+	return total
+}
+
+// WriteJSON writes the portfolio as indented JSON.
+//
+// @param w the writer
+// @return an error if the portfolio can't be written
+func (p *Portfolio) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// WriteCSV writes the portfolio as CSV, one row per address and denomination followed by the total rows.
+// Per-validator delegations, vesting schedules and veToken binds are only exported as JSON.
+//
+// @param w the writer
+// @return an error if the portfolio can't be written
+func (p *Portfolio) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"height", "address", "denom", "liquid", "delegated", "unbonding", "rewards", "vesting_locked", "total", "total_cys"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	height := fmt.Sprint(p.Height)
+	for _, account := range p.Accounts {
+		locked := sdk.NewCoins()
+		if account.Vesting != nil {
+			locked = account.Vesting.Locked
+		}
+		for _, coin := range account.Total {
+			denom := coin.Denom
+			row := []string{
+				height,
+				account.Address,
+				denom,
+				account.Liquid.AmountOf(denom).String(),
+				account.Delegated.AmountOf(denom).String(),
+				account.Unbonding.AmountOf(denom).String(),
+				account.Rewards.AmountOf(denom).String(),
+				locked.AmountOf(denom).String(),
+				coin.Amount.String(),
+				valueInCYS(sdk.NewCoins(coin), p.CGTToCYSRate).String(),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, coin := range p.Total {
+		row := []string{height, "total", coin.Denom, "", "", "", "", "", coin.Amount.String(), valueInCYS(sdk.NewCoins(coin), p.CGTToCYSRate).String()}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,506 @@
+package gosdk
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// Rebalance action types
+const (
+	RebalanceActionDelegate   = "delegate"
+	RebalanceActionRedelegate = "redelegate"
+	RebalanceActionUndelegate = "undelegate"
+)
+
+const (
+	defaultRebalanceMsgsPerTx = 20
+	defaultRebalanceTxTimeout = 6 * BlockTime
+)
+
+// RebalancePolicy describes how CGT stake should be spread across validators.
+type RebalancePolicy struct {
+	// MaxShare is the maximum share of the total stake a single validator may hold, e.g. 0.25
+	MaxShare sdk.Dec
+	// MaxCommission excludes validators with a higher commission rate, nil means no limit
+	MaxCommission *sdk.Dec
+	// BondedOnly excludes validators outside the active set
+	BondedOnly bool
+	// Validators restricts the eligible validators, empty means every validator
+	Validators []string
+	// AdditionalStake is the CGT, in base units, newly delegated by the plan
+	AdditionalStake math.Int
+	// ReleaseStake is the CGT, in base units, undelegated by the plan
+	ReleaseStake math.Int
+}
+
+// RebalanceAction is one message of a rebalance plan.
+type RebalanceAction struct {
+	Type         string   `json:"type"`
+	SrcValidator string   `json:"src_validator,omitempty"`
+	DstValidator string   `json:"dst_validator,omitempty"`
+	Amount       math.Int `json:"amount"`
+}
+
+// RebalancePlan is the list of actions moving the current delegations to their targets.
+type RebalancePlan struct {
+	Delegator string              `json:"delegator"`
+	Total     math.Int            `json:"total"`
+	Cap       math.Int            `json:"cap"`
+	Current   map[string]math.Int `json:"current"`
+	Target    map[string]math.Int `json:"target"`
+	Excluded  map[string]string   `json:"excluded"`
+	// Redelegating are the validators that received a redelegation still in progress, stake can't be redelegated away from them
+	Redelegating map[string]bool `json:"redelegating"`
+	// Blocked is the surplus left on Redelegating validators, a later rebalance moves it once the redelegation completes
+	Blocked map[string]math.Int `json:"blocked"`
+	Actions []RebalanceAction   `json:"actions"`
+}
+
+// RebalanceConfig configures ExecuteRebalance.
+type RebalanceConfig struct {
+	// DryRun returns the plan and its messages without broadcasting them
+	DryRun bool
+	// MaxMsgsPerTx is the maximum number of messages per transaction
+	MaxMsgsPerTx int
+	// TxTimeout is how long to wait for a transaction to be packed before broadcasting the next one
+	TxTimeout time.Duration
+}
+
+// RebalanceResult is the outcome of ExecuteRebalance.
+type RebalanceResult struct {
+	Plan *RebalancePlan
+	Msgs []sdk.Msg
+	// TxHashes are the hashes of the broadcast batches, empty for a dry run
+	TxHashes []string
+}
+
+// PlanRebalanceForDelegator queries the delegations, the redelegations in progress of a delegator and the validator set,
+// then plans a rebalance.
+//
+// @param delegatorAddress the address of the delegator
+// @param policy the rebalance policy
+// @return the rebalance plan, or an error if a query fails or the policy can't be satisfied
+func (s *Server) PlanRebalanceForDelegator(delegatorAddress string, policy RebalancePolicy) (*RebalancePlan, error) {
+	delegator, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		return nil, err
+	}
+
+	delegationMap, err := s.QueryDelegatorDelegations(delegator)
+	if err != nil {
+		return nil, err
+	}
+	delegations := make(map[string]math.Int)
+	for validator, coins := range delegationMap {
+		amount := sdk.NewCoins(coins...).AmountOf(CGTToken)
+		if amount.IsPositive() {
+			delegations[validator] = amount
+		}
+	}
+
+	redelegations, err := s.QueryRedelegations(delegator)
+	if err != nil {
+		return nil, err
+	}
+	redelegating := make(map[string]bool)
+	for _, redelegation := range redelegations {
+		if len(redelegation.Entries) != 0 {
+			redelegating[redelegation.Redelegation.ValidatorDstAddress] = true
+		}
+	}
+
+	validatorList, err := s.GetAllValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	return PlanRebalance(delegator, policy, delegations, redelegating, validatorList)
+}
+
+// PlanRebalance plans the minimal set of messages moving the delegations to a policy compliant distribution.
+//
+// Delegations to excluded validators are moved away entirely, delegations above the cap are trimmed to
+// it, and the freed stake fills the eligible validators with the most room first, so that as few
+// validators as possible receive stake. The chain rejects redelegating from a validator that itself
+// received a redelegation which is not complete yet, so the surplus of such a validator stays in place
+// and is reported in Blocked, only ReleaseStake is undelegated from it. The other validators are planned
+// around it and stay within the cap.
+//
+// @param delegator the address of the delegator
+// @param policy the rebalance policy
+// @param delegations the current CGT delegations keyed by validator address
+// @param redelegating the validators that received a redelegation still in progress, optional
+// @param validatorList the validator set
+// @return the rebalance plan, or an error if the eligible validators can't hold the total stake under the cap
+func PlanRebalance(delegator string, policy RebalancePolicy, delegations map[string]math.Int, redelegating map[string]bool, validatorList []stakingtypes.Validator) (*RebalancePlan, error) {
+	if policy.MaxShare.IsNil() || !policy.MaxShare.IsPositive() || policy.MaxShare.GT(sdk.OneDec()) {
+		return nil, fmt.Errorf("max share must be in (0, 1], got: %v", policy.MaxShare)
+	}
+	additional := policy.AdditionalStake
+	if additional.IsNil() {
+		additional = math.ZeroInt()
+	}
+	release := policy.ReleaseStake
+	if release.IsNil() {
+		release = math.ZeroInt()
+	}
+	if additional.IsNegative() || release.IsNegative() {
+		return nil, fmt.Errorf("additional and release stake can't be negative")
+	}
+	if additional.IsPositive() && release.IsPositive() {
+		return nil, fmt.Errorf("additional and release stake can't be both set")
+	}
+
+	plan := &RebalancePlan{
+		Delegator:    delegator,
+		Total:        math.ZeroInt(),
+		Current:      make(map[string]math.Int),
+		Target:       make(map[string]math.Int),
+		Excluded:     make(map[string]string),
+		Redelegating: make(map[string]bool),
+		Blocked:      make(map[string]math.Int),
+		Actions:      make([]RebalanceAction, 0),
+	}
+
+	for validator, amount := range delegations {
+		plan.Current[validator] = amount
+		plan.Total = plan.Total.Add(amount)
+		if redelegating[validator] {
+			plan.Redelegating[validator] = true
+		}
+	}
+	plan.Total = plan.Total.Add(additional).Sub(release)
+	if plan.Total.IsNegative() {
+		return nil, fmt.Errorf("release stake %v exceeds the delegated stake", release)
+	}
+	plan.Cap = policy.MaxShare.MulInt(plan.Total).TruncateInt()
+
+	eligible := make([]string, 0)
+	known := make(map[string]bool)
+	for _, validator := range validatorList {
+		known[validator.OperatorAddress] = true
+		if reason := rebalanceExclusion(policy, validator); reason != "" {
+			plan.Excluded[validator.OperatorAddress] = reason
+			continue
+		}
+		eligible = append(eligible, validator.OperatorAddress)
+	}
+	for validator := range delegations {
+		if !known[validator] {
+			plan.Excluded[validator] = "not in validator set"
+		}
+	}
+
+	target, err := assignRebalanceTargets(eligible, plan.Current, plan.Total, plan.Cap)
+	if err != nil {
+		return nil, err
+	}
+
+	// the surplus of a validator with a redelegation in progress can't move, stake released by the
+	// policy is taken from it first and the rest is planned around it
+	pinned := make(map[string]math.Int)
+	unreleased := release
+	for _, validator := range sortedKeys(plan.Redelegating) {
+		surplus := currentOf(plan.Current, validator).Sub(currentOf(target, validator))
+		if !surplus.IsPositive() {
+			continue
+		}
+		released := math.MinInt(surplus, unreleased)
+		unreleased = unreleased.Sub(released)
+		pinned[validator] = currentOf(plan.Current, validator).Sub(released)
+		if blocked := surplus.Sub(released); blocked.IsPositive() {
+			plan.Blocked[validator] = blocked
+		}
+	}
+	if len(pinned) > 0 {
+		others := make([]string, 0, len(eligible))
+		for _, validator := range eligible {
+			if _, ok := pinned[validator]; !ok {
+				others = append(others, validator)
+			}
+		}
+		rest := plan.Total
+		for _, amount := range pinned {
+			rest = rest.Sub(amount)
+		}
+
+		if target, err = assignRebalanceTargets(others, plan.Current, rest, plan.Cap); err != nil {
+			return nil, err
+		}
+		for validator, amount := range pinned {
+			if amount.IsPositive() {
+				target[validator] = amount
+			}
+		}
+	}
+	plan.Target = target
+
+	plan.Actions = planRebalanceActions(plan.Current, plan.Target, additional, plan.Redelegating)
+	return plan, nil
+}
+
+// assignRebalanceTargets keeps what already complies, then fills the validators with the most room first,
+// or trims the largest targets first when stake is released, until the targets sum to total.
+func assignRebalanceTargets(eligible []string, current map[string]math.Int, total math.Int, maxAmount math.Int) (map[string]math.Int, error) {
+	eligible = append([]string(nil), eligible...)
+	target := make(map[string]math.Int)
+
+	assigned := math.ZeroInt()
+	for _, validator := range eligible {
+		amount := math.MinInt(currentOf(current, validator), maxAmount)
+		target[validator] = amount
+		assigned = assigned.Add(amount)
+	}
+	if assigned.GT(total) {
+		sortByAmount(eligible, target, false)
+		excess := assigned.Sub(total)
+		for _, validator := range eligible {
+			trimmed := math.MinInt(target[validator], excess)
+			target[validator] = target[validator].Sub(trimmed)
+			excess = excess.Sub(trimmed)
+		}
+	} else {
+		sortByAmount(eligible, target, true)
+		remaining := total.Sub(assigned)
+		for _, validator := range eligible {
+			added := math.MinInt(maxAmount.Sub(target[validator]), remaining)
+			target[validator] = target[validator].Add(added)
+			remaining = remaining.Sub(added)
+		}
+		if remaining.IsPositive() {
+			return nil, fmt.Errorf("%v eligible validators can't hold %v under cap %v", len(eligible), total, maxAmount)
+		}
+	}
+	for validator, amount := range target {
+		if amount.IsZero() {
+			delete(target, validator)
+		}
+	}
+
+	return target, nil
+}
+
+// rebalanceExclusion returns why a validator is not eligible, or an empty string if it is.
+func rebalanceExclusion(policy RebalancePolicy, validator stakingtypes.Validator) string {
+	if validator.Jailed {
+		return "jailed"
+	}
+	if policy.BondedOnly && !validator.IsBonded() {
+		return "not bonded"
+	}
+	if policy.MaxCommission != nil && validator.Commission.Rate.GT(*policy.MaxCommission) {
+		return fmt.Sprintf("commission %v above %v", validator.Commission.Rate, *policy.MaxCommission)
+	}
+	if len(policy.Validators) != 0 && !containsString(policy.Validators, validator.OperatorAddress) {
+		return "not in allowed validators"
+	}
+
+	return ""
+}
+
+// planRebalanceActions pairs the surpluses with the deficits, largest first, so that few messages are needed.
+// The surplus of a validator in redelegating can't be redelegated, it must only be stake to release and is undelegated.
+func planRebalanceActions(current map[string]math.Int, target map[string]math.Int, additional math.Int, redelegating map[string]bool) []RebalanceAction {
+	surplus := make(map[string]math.Int)
+	deficit := make(map[string]math.Int)
+	for validator, amount := range current {
+		if diff := amount.Sub(currentOf(target, validator)); diff.IsPositive() {
+			surplus[validator] = diff
+		}
+	}
+	for validator, amount := range target {
+		if diff := amount.Sub(currentOf(current, validator)); diff.IsPositive() {
+			deficit[validator] = diff
+		}
+	}
+
+	srcList := sortedKeys(surplus)
+	sortByAmount(srcList, surplus, false)
+	dstList := sortedKeys(deficit)
+	sortByAmount(dstList, deficit, false)
+
+	actions := make([]RebalanceAction, 0)
+	// new stake goes to the deficits first, it needs no redelegation
+	for _, dst := range dstList {
+		if !additional.IsPositive() {
+			break
+		}
+		amount := math.MinInt(additional, deficit[dst])
+		actions = append(actions, RebalanceAction{Type: RebalanceActionDelegate, DstValidator: dst, Amount: amount})
+		additional = additional.Sub(amount)
+		deficit[dst] = deficit[dst].Sub(amount)
+	}
+
+	for _, src := range srcList {
+		for _, dst := range dstList {
+			if redelegating[src] || !surplus[src].IsPositive() {
+				break
+			}
+			if !deficit[dst].IsPositive() {
+				continue
+			}
+			amount := math.MinInt(surplus[src], deficit[dst])
+			actions = append(actions, RebalanceAction{Type: RebalanceActionRedelegate, SrcValidator: src, DstValidator: dst, Amount: amount})
+			surplus[src] = surplus[src].Sub(amount)
+			deficit[dst] = deficit[dst].Sub(amount)
+		}
+		if surplus[src].IsPositive() {
+			actions = append(actions, RebalanceAction{Type: RebalanceActionUndelegate, SrcValidator: src, Amount: surplus[src]})
+		}
+	}
+
+	return actions
+}
+
+// Msgs converts the plan actions to staking messages.
+//
+// @return the list of messages
+func (p *RebalancePlan) Msgs() []sdk.Msg {
+	msgList := make([]sdk.Msg, 0, len(p.Actions))
+	for _, action := range p.Actions {
+		amount := sdk.NewCoin(CGTToken, action.Amount)
+		switch action.Type {
+		case RebalanceActionDelegate:
+			msgList = append(msgList, &stakingtypes.MsgDelegate{
+				DelegatorAddress: p.Delegator,
+				ValidatorAddress: action.DstValidator,
+				Amount:           amount,
+			})
+		case RebalanceActionRedelegate:
+			msgList = append(msgList, &stakingtypes.MsgBeginRedelegate{
+				DelegatorAddress:    p.Delegator,
+				ValidatorSrcAddress: action.SrcValidator,
+				ValidatorDstAddress: action.DstValidator,
+				Amount:              amount,
+			})
+		case RebalanceActionUndelegate:
+			msgList = append(msgList, &stakingtypes.MsgUndelegate{
+				DelegatorAddress: p.Delegator,
+				ValidatorAddress: action.SrcValidator,
+				Amount:           amount,
+			})
+		}
+	}
+
+	return msgList
+}
+
+// String renders the plan as a human readable summary.
+func (p *RebalancePlan) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "rebalance plan for %v, total: %v%v, cap: %v%v\n", p.Delegator, p.Total, CGTToken, p.Cap, CGTToken)
+	for _, validator := range sortedKeys(p.Excluded) {
+		fmt.Fprintf(&builder, "  excluded %v: %v\n", validator, p.Excluded[validator])
+	}
+	for _, validator := range sortedKeys(p.Blocked) {
+		fmt.Fprintf(&builder, "  blocked %v%v on %v, redelegation in progress\n", p.Blocked[validator], CGTToken, validator)
+	}
+	if len(p.Actions) == 0 {
+		builder.WriteString("  nothing to do\n")
+	}
+	for _, action := range p.Actions {
+		switch action.Type {
+		case RebalanceActionDelegate:
+			fmt.Fprintf(&builder, "  delegate %v%v to %v\n", action.Amount, CGTToken, action.DstValidator)
+		case RebalanceActionRedelegate:
+			fmt.Fprintf(&builder, "  redelegate %v%v from %v to %v\n", action.Amount, CGTToken, action.SrcValidator, action.DstValidator)
+		case RebalanceActionUndelegate:
+			fmt.Fprintf(&builder, "  undelegate %v%v from %v\n", action.Amount, CGTToken, action.SrcValidator)
+		}
+	}
+
+	return builder.String()
+}
+
+// ExecuteRebalance broadcasts a rebalance plan in batches, waiting for each batch to be packed before the next one.
+//
+// @param signer the Signer instance of the delegator
+// @param plan the rebalance plan
+// @param config the execution configuration
+// @return the plan, its messages and the transaction hashes of the batches broadcast so far, or an error if a batch fails
+func (s *Server) ExecuteRebalance(signer Signer, plan *RebalancePlan, config RebalanceConfig) (*RebalanceResult, error) {
+	if plan.Delegator != signer.CosmosAddr.String() {
+		return nil, fmt.Errorf("plan delegator %v doesn't match signer %v", plan.Delegator, signer.CosmosAddr.String())
+	}
+	if config.MaxMsgsPerTx <= 0 {
+		config.MaxMsgsPerTx = defaultRebalanceMsgsPerTx
+	}
+	if config.TxTimeout <= 0 {
+		config.TxTimeout = defaultRebalanceTxTimeout
+	}
+
+	result := &RebalanceResult{
+		Plan:     plan,
+		Msgs:     plan.Msgs(),
+		TxHashes: make([]string, 0),
+	}
+	if config.DryRun {
+		log.Printf("dry run, %v", plan.String())
+		return result, nil
+	}
+
+	msgList := result.Msgs
+	for start := 0; start < len(msgList); start += config.MaxMsgsPerTx {
+		end := start + config.MaxMsgsPerTx
+		if end > len(msgList) {
+			end = len(msgList)
+		}
+
+		txHash, err := s.buildAndBroadcastSimulatedCosmosTx(signer, msgList[start:end])
+		if err != nil {
+			log.Printf("error when broadcast rebalance msgs %v-%v, err: %v", start, end, err.Error())
+			return result, err
+		}
+		result.TxHashes = append(result.TxHashes, txHash)
+
+		resp, err := s.WaitTx(txHash, config.TxTimeout)
+		if err != nil {
+			return result, err
+		}
+		if resp.Code != 0 {
+			return result, fmt.Errorf("rebalance tx %v failed, log: %v", txHash, resp.RawLog)
+		}
+	}
+
+	return result, nil
+}
+
+func currentOf(amounts map[string]math.Int, validator string) math.Int {
+	if amount, ok := amounts[validator]; ok {
+		return amount
+	}
+
+	return math.ZeroInt()
+}
+
+// sortByAmount sorts validators by amount, ties are ordered by address.
+func sortByAmount(validatorList []string, amounts map[string]math.Int, ascending bool) {
+	sort.SliceStable(validatorList, func(i, j int) bool {
+		a, b := currentOf(amounts, validatorList[i]), currentOf(amounts, validatorList[j])
+		if a.Equal(b) {
+			return validatorList[i] < validatorList[j]
+		}
+		if ascending {
+			return a.LT(b)
+		}
+		return a.GT(b)
+	})
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,295 @@
+package gosdk
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func newRebalanceValidator(address string, commission string, status stakingtypes.BondStatus, jailed bool) stakingtypes.Validator {
+	return stakingtypes.Validator{
+		OperatorAddress: address,
+		Status:          status,
+		Jailed:          jailed,
+		Commission:      stakingtypes.Commission{CommissionRates: stakingtypes.CommissionRates{Rate: sdk.MustNewDecFromStr(commission)}},
+	}
+}
+
+func amountsOf(pairs ...interface{}) map[string]math.Int {
+	result := make(map[string]math.Int)
+	for i := 0; i < len(pairs); i += 2 {
+		result[pairs[i].(string)] = math.NewInt(int64(pairs[i+1].(int)))
+	}
+
+	return result
+}
+
+func assertRebalanceActions(t *testing.T, got []RebalanceAction, want []RebalanceAction) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("actions = %+v, want %+v", got, want)
+	}
+	for i := range got {
+		if got[i].Type != want[i].Type || got[i].SrcValidator != want[i].SrcValidator ||
+			got[i].DstValidator != want[i].DstValidator || !got[i].Amount.Equal(want[i].Amount) {
+			t.Errorf("action %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPlanRebalance(t *testing.T) {
+	maxCommission := sdk.MustNewDecFromStr("0.1")
+	validatorList := []stakingtypes.Validator{
+		newRebalanceValidator("valA", "0.05", stakingtypes.Bonded, false),
+		newRebalanceValidator("valB", "0.05", stakingtypes.Bonded, false),
+		newRebalanceValidator("valC", "0.05", stakingtypes.Bonded, false),
+		newRebalanceValidator("valJailed", "0.05", stakingtypes.Unbonding, true),
+		newRebalanceValidator("valUnbonded", "0.05", stakingtypes.Unbonded, false),
+		newRebalanceValidator("valExpensive", "0.2", stakingtypes.Bonded, false),
+	}
+
+	tests := []struct {
+		name         string
+		policy       RebalancePolicy
+		delegations  map[string]math.Int
+		redelegating map[string]bool
+		validators   []stakingtypes.Validator
+		wantTarget   map[string]math.Int
+		wantExcluded []string
+		wantBlocked  map[string]math.Int
+		wantActions  []RebalanceAction
+		wantErr      bool
+	}{
+		{
+			name:        "trim above cap",
+			policy:      RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), Validators: []string{"valA", "valB"}},
+			delegations: amountsOf("valA", 100),
+			validators:  validatorList,
+			wantTarget:  amountsOf("valA", 50, "valB", 50),
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionRedelegate, SrcValidator: "valA", DstValidator: "valB", Amount: math.NewInt(50)},
+			},
+		},
+		{
+			name:         "move away from excluded validators",
+			policy:       RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), MaxCommission: &maxCommission, BondedOnly: true},
+			delegations:  amountsOf("valJailed", 60, "valExpensive", 40),
+			validators:   validatorList,
+			wantTarget:   amountsOf("valA", 50, "valB", 50),
+			wantExcluded: []string{"valExpensive", "valJailed", "valUnbonded"},
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionRedelegate, SrcValidator: "valJailed", DstValidator: "valA", Amount: math.NewInt(50)},
+				{Type: RebalanceActionRedelegate, SrcValidator: "valJailed", DstValidator: "valB", Amount: math.NewInt(10)},
+				{Type: RebalanceActionRedelegate, SrcValidator: "valExpensive", DstValidator: "valB", Amount: math.NewInt(40)},
+			},
+		},
+		{
+			name:         "unknown validator",
+			policy:       RebalancePolicy{MaxShare: sdk.OneDec(), Validators: []string{"valA"}},
+			delegations:  amountsOf("valGone", 10),
+			validators:   validatorList[:1],
+			wantTarget:   amountsOf("valA", 10),
+			wantExcluded: []string{"valGone"},
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionRedelegate, SrcValidator: "valGone", DstValidator: "valA", Amount: math.NewInt(10)},
+			},
+		},
+		{
+			name:        "delegate additional stake",
+			policy:      RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), Validators: []string{"valA", "valB"}, AdditionalStake: math.NewInt(60)},
+			delegations: amountsOf("valA", 40),
+			validators:  validatorList,
+			wantTarget:  amountsOf("valA", 50, "valB", 50),
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionDelegate, DstValidator: "valB", Amount: math.NewInt(50)},
+				{Type: RebalanceActionDelegate, DstValidator: "valA", Amount: math.NewInt(10)},
+			},
+		},
+		{
+			name:        "release stake from the largest delegation",
+			policy:      RebalancePolicy{MaxShare: sdk.OneDec(), Validators: []string{"valA", "valB"}, ReleaseStake: math.NewInt(20)},
+			delegations: amountsOf("valA", 60, "valB", 40),
+			validators:  validatorList,
+			wantTarget:  amountsOf("valA", 40, "valB", 40),
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionUndelegate, SrcValidator: "valA", Amount: math.NewInt(20)},
+			},
+		},
+		{
+			name:         "keep the surplus of a validator with a redelegation in progress",
+			policy:       RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), Validators: []string{"valA", "valB"}},
+			delegations:  amountsOf("valA", 100),
+			redelegating: map[string]bool{"valA": true},
+			validators:   validatorList,
+			wantTarget:   amountsOf("valA", 100),
+			wantBlocked:  amountsOf("valA", 50),
+			wantActions:  []RebalanceAction{},
+		},
+		{
+			name:         "plan around a validator with a redelegation in progress",
+			policy:       RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.3"), Validators: []string{"valA", "valB", "valC", "valD"}},
+			delegations:  amountsOf("valA", 60, "valC", 40),
+			redelegating: map[string]bool{"valA": true},
+			validators: append(validatorList[:3:3],
+				newRebalanceValidator("valD", "0.05", stakingtypes.Bonded, false)),
+			wantTarget:  amountsOf("valA", 60, "valB", 10, "valC", 30),
+			wantBlocked: amountsOf("valA", 30),
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionRedelegate, SrcValidator: "valC", DstValidator: "valB", Amount: math.NewInt(10)},
+			},
+		},
+		{
+			name:         "release stake from a validator with a redelegation in progress",
+			policy:       RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), Validators: []string{"valA", "valB"}, ReleaseStake: math.NewInt(30)},
+			delegations:  amountsOf("valA", 100),
+			redelegating: map[string]bool{"valA": true},
+			validators:   validatorList,
+			wantTarget:   amountsOf("valA", 70),
+			wantBlocked:  amountsOf("valA", 35),
+			wantActions: []RebalanceAction{
+				{Type: RebalanceActionUndelegate, SrcValidator: "valA", Amount: math.NewInt(30)},
+			},
+		},
+		{
+			name:        "compliant delegations",
+			policy:      RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), Validators: []string{"valA", "valB"}},
+			delegations: amountsOf("valA", 50, "valB", 50),
+			validators:  validatorList,
+			wantTarget:  amountsOf("valA", 50, "valB", 50),
+			wantActions: []RebalanceAction{},
+		},
+		{
+			name:        "cap can't hold the stake",
+			policy:      RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("0.5"), Validators: []string{"valA"}},
+			delegations: amountsOf("valA", 100),
+			validators:  validatorList,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid max share",
+			policy:      RebalancePolicy{MaxShare: sdk.MustNewDecFromStr("1.5")},
+			delegations: amountsOf("valA", 100),
+			validators:  validatorList,
+			wantErr:     true,
+		},
+		{
+			name:        "additional and release stake",
+			policy:      RebalancePolicy{MaxShare: sdk.OneDec(), AdditionalStake: math.NewInt(1), ReleaseStake: math.NewInt(1)},
+			delegations: amountsOf("valA", 100),
+			validators:  validatorList,
+			wantErr:     true,
+		},
+		{
+			name:        "release more than delegated",
+			policy:      RebalancePolicy{MaxShare: sdk.OneDec(), ReleaseStake: math.NewInt(101)},
+			delegations: amountsOf("valA", 100),
+			validators:  validatorList,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanRebalance("delegator", tt.policy, tt.delegations, tt.redelegating, tt.validators)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanRebalance() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(plan.Target) != len(tt.wantTarget) {
+				t.Errorf("target = %v, want %v", plan.Target, tt.wantTarget)
+			}
+			for validator, amount := range tt.wantTarget {
+				if !currentOf(plan.Target, validator).Equal(amount) {
+					t.Errorf("target of %v = %v, want %v", validator, currentOf(plan.Target, validator), amount)
+				}
+			}
+			for _, validator := range tt.wantExcluded {
+				if plan.Excluded[validator] == "" {
+					t.Errorf("%v is not excluded, excluded: %v", validator, plan.Excluded)
+				}
+			}
+			if len(plan.Blocked) != len(tt.wantBlocked) {
+				t.Errorf("blocked = %v, want %v", plan.Blocked, tt.wantBlocked)
+			}
+			for validator, amount := range tt.wantBlocked {
+				if !currentOf(plan.Blocked, validator).Equal(amount) {
+					t.Errorf("blocked on %v = %v, want %v", validator, currentOf(plan.Blocked, validator), amount)
+				}
+			}
+			assertRebalanceActions(t, plan.Actions, tt.wantActions)
+			if msgs := plan.Msgs(); len(msgs) != len(plan.Actions) {
+				t.Errorf("plan has %v msgs for %v actions", len(msgs), len(plan.Actions))
+			}
+		})
+	}
+}
+
+func TestPlanRebalanceActions(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      map[string]math.Int
+		target       map[string]math.Int
+		additional   math.Int
+		redelegating map[string]bool
+		want         []RebalanceAction
+	}{
+		{
+			name:    "largest surplus fills largest deficit first",
+			current: amountsOf("valA", 70, "valB", 30),
+			target:  amountsOf("valC", 60, "valD", 40),
+			want: []RebalanceAction{
+				{Type: RebalanceActionRedelegate, SrcValidator: "valA", DstValidator: "valC", Amount: math.NewInt(60)},
+				{Type: RebalanceActionRedelegate, SrcValidator: "valA", DstValidator: "valD", Amount: math.NewInt(10)},
+				{Type: RebalanceActionRedelegate, SrcValidator: "valB", DstValidator: "valD", Amount: math.NewInt(30)},
+			},
+		},
+		{
+			name:       "additional stake before redelegations",
+			current:    amountsOf("valA", 80),
+			target:     amountsOf("valA", 40, "valB", 60),
+			additional: math.NewInt(20),
+			want: []RebalanceAction{
+				{Type: RebalanceActionDelegate, DstValidator: "valB", Amount: math.NewInt(20)},
+				{Type: RebalanceActionRedelegate, SrcValidator: "valA", DstValidator: "valB", Amount: math.NewInt(40)},
+			},
+		},
+		{
+			name:    "surplus without deficit",
+			current: amountsOf("valA", 80, "valB", 20),
+			target:  amountsOf("valA", 50, "valB", 20),
+			want: []RebalanceAction{
+				{Type: RebalanceActionUndelegate, SrcValidator: "valA", Amount: math.NewInt(30)},
+			},
+		},
+		{
+			name:         "released stake of a redelegating source is undelegated",
+			current:      amountsOf("valA", 50, "valB", 50),
+			target:       amountsOf("valC", 50),
+			redelegating: map[string]bool{"valA": true},
+			want: []RebalanceAction{
+				{Type: RebalanceActionUndelegate, SrcValidator: "valA", Amount: math.NewInt(50)},
+				{Type: RebalanceActionRedelegate, SrcValidator: "valB", DstValidator: "valC", Amount: math.NewInt(50)},
+			},
+		},
+		{
+			name:    "nothing to do",
+			current: amountsOf("valA", 50),
+			target:  amountsOf("valA", 50),
+			want:    []RebalanceAction{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			additional := tt.additional
+			if additional.IsNil() {
+				additional = math.ZeroInt()
+			}
+			assertRebalanceActions(t, planRebalanceActions(tt.current, tt.target, additional, tt.redelegating), tt.want)
+		})
+	}
+}
@@ -1,15 +1,19 @@
 package gosdk
 
 import (
+	"context"
 	"log"
+	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
@@ -28,6 +32,9 @@ type Server struct {
 	GasCoin  string
 	GasPrice int64
 	GasLimit uint64
+
+	// height pins module queries to a block height, 0 queries the latest state
+	height int64
 }
 
 // NewServerWithGRPC creates a new Server instance with a gRPC connection.
@@ -94,6 +101,31 @@ func (s *Server) KeepGrpcConn() error {
 	return nil
 }
 
+// AtHeight returns a copy of the Server whose module queries, e.g. balances, delegations or rewards,
+// read the state at a given height. Transactions and block queries are not affected, the account
+// number and sequence of a transaction are always read from the latest state.
+//
+// @param height the block height, 0 for the latest state
+// @return a new Server instance sharing the connection of s
+func (s *Server) AtHeight(height int64) *Server {
+	pinned := *s
+	pinned.height = height
+	return &pinned
+}
+
+// Height returns the block height module queries are pinned to, 0 when they read the latest state.
+func (s *Server) Height() int64 {
+	return s.height
+}
+
+func (s *Server) queryContext() context.Context {
+	if s.height <= 0 {
+		return context.Background()
+	}
+
+	return metadata.AppendToOutgoingContext(context.Background(), grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(s.height, 10))
+}
+
 func (s *Server) Close() error {
 	return s.Conn.Close()
 }
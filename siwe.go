@@ -0,0 +1,458 @@
+package gosdk
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	cysicTypes "github.com/hack2fun/gosdk/types/cysic"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	siweVersion          = "1"
+	siweHeaderSuffix     = " wants you to sign in with your Ethereum account:"
+	siweNonceAlphabet    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	siweNonceLength      = 17
+	siweMinNonceLength   = 8
+	siweURIPrefix        = "URI: "
+	siweVersionPrefix    = "Version: "
+	siweChainIDPrefix    = "Chain ID: "
+	siweNoncePrefix      = "Nonce: "
+	siweIssuedAtPrefix   = "Issued At: "
+	siweExpirationPrefix = "Expiration Time: "
+	siweNotBeforePrefix  = "Not Before: "
+	siweRequestIDPrefix  = "Request ID: "
+	siweResourcesHeader  = "Resources:"
+	siweResourcePrefix   = "- "
+)
+
+// SiweMessage is a Sign-In-With-Ethereum (EIP-4361) message.
+type SiweMessage struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// SiweVerifyResult holds the outcome of a successful SIWE verification.
+type SiweVerifyResult struct {
+	Message   *SiweMessage
+	EthAddr   string
+	CysicAddr string
+}
+
+// SiweNonceStore tracks the nonces handed out to clients so every nonce can only be used once.
+type SiweNonceStore interface {
+	// UseNonce consumes the nonce, returning an error if it was never issued, already used or expired.
+	UseNonce(nonce string) error
+}
+
+// MemorySiweNonceStore is an in-memory SiweNonceStore, suitable for a single backend instance.
+type MemorySiweNonceStore struct {
+	lock   sync.Mutex
+	ttl    time.Duration
+	nonces map[string]time.Time
+}
+
+// NewMemorySiweNonceStore creates a new in-memory nonce store.
+//
+// @param ttl how long an issued nonce stays valid, zero means forever
+// @return a new MemorySiweNonceStore instance
+func NewMemorySiweNonceStore(ttl time.Duration) *MemorySiweNonceStore {
+	return &MemorySiweNonceStore{
+		ttl:    ttl,
+		nonces: make(map[string]time.Time),
+	}
+}
+
+// NewNonce generates and records a new nonce.
+//
+// @return the nonce, or an error if generation fails
+func (m *MemorySiweNonceStore) NewNonce() (string, error) {
+	nonce, err := GenerateSiweNonce()
+	if err != nil {
+		return "", err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	for key, issuedAt := range m.nonces {
+		if m.ttl > 0 && now.Sub(issuedAt) > m.ttl {
+			delete(m.nonces, key)
+		}
+	}
+	m.nonces[nonce] = now
+
+	return nonce, nil
+}
+
+// UseNonce consumes a nonce previously issued by NewNonce.
+//
+// @param nonce the nonce to consume
+// @return an error if the nonce is unknown, already used or expired
+func (m *MemorySiweNonceStore) UseNonce(nonce string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	issuedAt, exist := m.nonces[nonce]
+	if !exist {
+		return fmt.Errorf("nonce %v not issued or already used", nonce)
+	}
+	delete(m.nonces, nonce)
+
+	if m.ttl > 0 && time.Since(issuedAt) > m.ttl {
+		return fmt.Errorf("nonce %v expired", nonce)
+	}
+
+	return nil
+}
+
+// GenerateSiweNonce generates a random alphanumeric nonce for a SIWE message.
+//
+// @return the nonce, or an error if the random source fails
+func GenerateSiweNonce() (string, error) {
+	max := big.NewInt(int64(len(siweNonceAlphabet)))
+	result := make([]byte, siweNonceLength)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			log.Printf("error when generate siwe nonce, err: %v", err.Error())
+			return "", err
+		}
+		result[i] = siweNonceAlphabet[n.Int64()]
+	}
+
+	return string(result), nil
+}
+
+// NewSiweMessage creates a SIWE message for an account on the given cysic chain.
+//
+// @param domain the domain requesting the signature
+// @param address the 0x or cysic address of the account signing in
+// @param uri the URI referring to the resource that is the subject of the signing
+// @param statement the human-readable statement, may be empty
+// @param chainID the cysic chain ID, e.g. cysicmint_9001-1
+// @param nonce the nonce issued by the backend
+// @param expiration how long the message stays valid, zero means no expiration
+// @return a new SiweMessage instance, or an error if the address or chain ID is invalid
+func NewSiweMessage(domain, address, uri, statement, chainID, nonce string, expiration time.Duration) (*SiweMessage, error) {
+	ethAddr, err := ConvertToETHAddress(address)
+	if err != nil {
+		log.Printf("error when convert addr: %v to eth addr, err: %v", address, err.Error())
+		return nil, err
+	}
+
+	evmChainID, err := cysicTypes.ParseChainID(chainID)
+	if err != nil {
+		log.Printf("error when parse chain id: %v, err: %v", chainID, err.Error())
+		return nil, err
+	}
+
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	msg := &SiweMessage{
+		Domain:    domain,
+		Address:   ethAddr,
+		Statement: statement,
+		URI:       uri,
+		Version:   siweVersion,
+		ChainID:   evmChainID.Int64(),
+		Nonce:     nonce,
+		IssuedAt:  issuedAt,
+	}
+	if expiration > 0 {
+		expirationTime := issuedAt.Add(expiration)
+		msg.ExpirationTime = &expirationTime
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// Validate checks the message fields required by EIP-4361.
+//
+// @return an error if a field is missing or malformed
+func (m *SiweMessage) Validate() error {
+	if m.Domain == "" {
+		return fmt.Errorf("siwe domain can't be empty")
+	}
+	if !common.IsHexAddress(m.Address) {
+		return fmt.Errorf("siwe address %v is not a valid hex address", m.Address)
+	}
+	if strings.Contains(m.Statement, "\n") {
+		return fmt.Errorf("siwe statement can't contain line breaks")
+	}
+	if m.URI == "" {
+		return fmt.Errorf("siwe uri can't be empty")
+	}
+	if m.Version != siweVersion {
+		return fmt.Errorf("unsupported siwe version: %v", m.Version)
+	}
+	if m.ChainID <= 0 {
+		return fmt.Errorf("invalid siwe chain id: %v", m.ChainID)
+	}
+	if len(m.Nonce) < siweMinNonceLength {
+		return fmt.Errorf("siwe nonce must be at least %v characters", siweMinNonceLength)
+	}
+	for _, c := range m.Nonce {
+		if !strings.ContainsRune(siweNonceAlphabet, c) {
+			return fmt.Errorf("siwe nonce must be alphanumeric")
+		}
+	}
+	if m.IssuedAt.IsZero() {
+		return fmt.Errorf("siwe issued at can't be empty")
+	}
+
+	return nil
+}
+
+// String returns the EIP-4361 text representation of the message, which is what gets signed.
+func (m *SiweMessage) String() string {
+	var builder strings.Builder
+
+	builder.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	builder.WriteString(common.HexToAddress(m.Address).Hex() + "\n")
+	builder.WriteString("\n")
+	if m.Statement != "" {
+		builder.WriteString(m.Statement + "\n")
+	}
+	builder.WriteString("\n")
+
+	builder.WriteString(siweURIPrefix + m.URI + "\n")
+	builder.WriteString(siweVersionPrefix + m.Version + "\n")
+	builder.WriteString(fmt.Sprintf("%v%d\n", siweChainIDPrefix, m.ChainID))
+	builder.WriteString(siweNoncePrefix + m.Nonce + "\n")
+	builder.WriteString(siweIssuedAtPrefix + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		builder.WriteString("\n" + siweExpirationPrefix + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		builder.WriteString("\n" + siweNotBeforePrefix + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		builder.WriteString("\n" + siweRequestIDPrefix + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		builder.WriteString("\n" + siweResourcesHeader)
+		for _, resource := range m.Resources {
+			builder.WriteString("\n" + siweResourcePrefix + resource)
+		}
+	}
+
+	return builder.String()
+}
+
+// ParseSiweMessage parses the EIP-4361 text representation of a message.
+//
+// @param message the message text
+// @return the parsed SiweMessage, or an error if the text is malformed
+func ParseSiweMessage(message string) (*SiweMessage, error) {
+	lines := strings.Split(message, "\n")
+	if len(lines) < 8 {
+		return nil, fmt.Errorf("siwe message too short")
+	}
+
+	if !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("invalid siwe header: %v", lines[0])
+	}
+	msg := &SiweMessage{
+		Domain:  strings.TrimSuffix(lines[0], siweHeaderSuffix),
+		Address: lines[1],
+	}
+	if lines[2] != "" {
+		return nil, fmt.Errorf("expected empty line after siwe address")
+	}
+
+	idx := 3
+	if lines[idx] != "" {
+		msg.Statement = lines[idx]
+		idx++
+	}
+	if idx >= len(lines) || lines[idx] != "" {
+		return nil, fmt.Errorf("expected empty line after siwe statement")
+	}
+	idx++
+
+	readField := func(prefix string, required bool) (string, error) {
+		if idx < len(lines) && strings.HasPrefix(lines[idx], prefix) {
+			value := strings.TrimPrefix(lines[idx], prefix)
+			idx++
+			return value, nil
+		}
+		if required {
+			return "", fmt.Errorf("siwe field %q missing", strings.TrimSuffix(prefix, ": "))
+		}
+		return "", nil
+	}
+	readTime := func(prefix string, required bool) (*time.Time, error) {
+		value, err := readField(prefix, required)
+		if err != nil || value == "" {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid siwe %v: %v", strings.TrimSuffix(prefix, ": "), err)
+		}
+		return &t, nil
+	}
+
+	var err error
+	if msg.URI, err = readField(siweURIPrefix, true); err != nil {
+		return nil, err
+	}
+	if msg.Version, err = readField(siweVersionPrefix, true); err != nil {
+		return nil, err
+	}
+	chainID, err := readField(siweChainIDPrefix, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Sscanf(chainID, "%d", &msg.ChainID); err != nil {
+		return nil, fmt.Errorf("invalid siwe chain id: %v", chainID)
+	}
+	if msg.Nonce, err = readField(siweNoncePrefix, true); err != nil {
+		return nil, err
+	}
+	issuedAt, err := readTime(siweIssuedAtPrefix, true)
+	if err != nil {
+		return nil, err
+	}
+	msg.IssuedAt = *issuedAt
+	if msg.ExpirationTime, err = readTime(siweExpirationPrefix, false); err != nil {
+		return nil, err
+	}
+	if msg.NotBefore, err = readTime(siweNotBeforePrefix, false); err != nil {
+		return nil, err
+	}
+	if msg.RequestID, err = readField(siweRequestIDPrefix, false); err != nil {
+		return nil, err
+	}
+	if idx < len(lines) && lines[idx] == siweResourcesHeader {
+		idx++
+		for idx < len(lines) && strings.HasPrefix(lines[idx], siweResourcePrefix) {
+			msg.Resources = append(msg.Resources, strings.TrimPrefix(lines[idx], siweResourcePrefix))
+			idx++
+		}
+	}
+	if idx != len(lines) {
+		return nil, fmt.Errorf("unexpected siwe line: %v", lines[idx])
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	if common.HexToAddress(msg.Address).Hex() != msg.Address {
+		return nil, fmt.Errorf("siwe address %v is not EIP-55 checksummed", msg.Address)
+	}
+
+	return msg, nil
+}
+
+// SignSiweMessage signs a SIWE message with the Ethereum personal sign algorithm.
+//
+// @param s the Signer instance
+// @param msg the message to sign
+// @return the message text, the signature, or an error if signing fails
+func (s *Signer) SignSiweMessage(msg *SiweMessage) (string, []byte, error) {
+	if msg == nil {
+		return "", nil, fmt.Errorf("siwe message is nil")
+	}
+	if common.HexToAddress(msg.Address) != s.EthAddr {
+		return "", nil, fmt.Errorf("siwe address %v not match signer %v", msg.Address, s.EthAddr.String())
+	}
+
+	text := msg.String()
+	sig, err := s.EthPersonalSign([]byte(text))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return text, sig, nil
+}
+
+// VerifySiweMessage parses and verifies a signed SIWE message.
+//
+// The signature, domain, chain ID and validity window are checked first, the nonce is
+// consumed from nonceStore last so that a rejected message does not burn it.
+//
+// @param message the message text that was signed
+// @param sig the personal signature of the message
+// @param domain the expected domain
+// @param chainID the expected cysic chain ID, e.g. cysicmint_9001-1
+// @param nonceStore the store used to check and consume the nonce
+// @return the verified message with its 0x and cysic addresses, or an error if verification fails
+func VerifySiweMessage(message string, sig []byte, domain string, chainID string, nonceStore SiweNonceStore) (*SiweVerifyResult, error) {
+	msg, err := ParseSiweMessage(message)
+	if err != nil {
+		log.Printf("error when parse siwe message, err: %v", err.Error())
+		return nil, err
+	}
+
+	if msg.Domain != domain {
+		return nil, fmt.Errorf("siwe domain mismatch, expected: %v, got: %v", domain, msg.Domain)
+	}
+
+	evmChainID, err := cysicTypes.ParseChainID(chainID)
+	if err != nil {
+		log.Printf("error when parse chain id: %v, err: %v", chainID, err.Error())
+		return nil, err
+	}
+	if evmChainID.Int64() != msg.ChainID {
+		return nil, fmt.Errorf("siwe chain id mismatch, expected: %v, got: %v", evmChainID, msg.ChainID)
+	}
+
+	now := time.Now()
+	if msg.ExpirationTime != nil && !now.Before(*msg.ExpirationTime) {
+		return nil, fmt.Errorf("siwe message expired at %v", msg.ExpirationTime.Format(time.RFC3339))
+	}
+	if msg.NotBefore != nil && now.Before(*msg.NotBefore) {
+		return nil, fmt.Errorf("siwe message not valid before %v", msg.NotBefore.Format(time.RFC3339))
+	}
+
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("invalid signature length: %v", len(sig))
+	}
+	// VerifyEthPersonalSignature normalizes the recovery id in place
+	sigCopy := make([]byte, len(sig))
+	copy(sigCopy, sig)
+	if !VerifyEthPersonalSignature(msg.Address, []byte(message), sigCopy) {
+		return nil, fmt.Errorf("invalid siwe signature for %v", msg.Address)
+	}
+
+	if nonceStore == nil {
+		return nil, fmt.Errorf("nonceStore is nil")
+	}
+	if err := nonceStore.UseNonce(msg.Nonce); err != nil {
+		log.Printf("error when use siwe nonce: %v, err: %v", msg.Nonce, err.Error())
+		return nil, err
+	}
+
+	ethAddr, cysicAddr, err := ConvertAddress(msg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SiweVerifyResult{
+		Message:   msg,
+		EthAddr:   ethAddr,
+		CysicAddr: cysicAddr,
+	}, nil
+}
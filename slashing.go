@@ -0,0 +1,176 @@
+package gosdk
+
+import (
+	"fmt"
+	"log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+// ValidatorUptime is the share of blocks signed by a validator over a range of heights.
+type ValidatorUptime struct {
+	ConsensusAddress string  `json:"consensus_address"`
+	FromHeight       int64   `json:"from_height"`
+	ToHeight         int64   `json:"to_height"`
+	SignedBlocks     int64   `json:"signed_blocks"`
+	MissedBlocks     int64   `json:"missed_blocks"`
+	Uptime           sdk.Dec `json:"uptime"`
+}
+
+// GetSlashingParams retrieves the parameters of the slashing module, e.g. the signed blocks window.
+//
+// @return the slashing parameters, or an error if the query fails
+func (s *Server) GetSlashingParams() (*slashingtypes.Params, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := slashingtypes.NewQueryClient(s.Conn)
+	resp, err := client.Params(s.queryContext(), &slashingtypes.QueryParamsRequest{})
+	if err != nil {
+		log.Printf("could not query slashing params: %v", err)
+		return nil, err
+	}
+
+	return &resp.Params, nil
+}
+
+// GetSigningInfo retrieves the signing info of a validator by consensus address.
+//
+// @param consAddr the bech32 consensus address of the validator
+// @return the signing info, or an error if the query fails
+func (s *Server) GetSigningInfo(consAddr string) (*slashingtypes.ValidatorSigningInfo, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := slashingtypes.NewQueryClient(s.Conn)
+	resp, err := client.SigningInfo(s.queryContext(), &slashingtypes.QuerySigningInfoRequest{ConsAddress: consAddr})
+	if err != nil {
+		log.Printf("could not query signing info of %v: %v", consAddr, err)
+		return nil, err
+	}
+
+	return &resp.ValSigningInfo, nil
+}
+
+// GetSigningInfos retrieves the signing infos of all validators.
+//
+// @return a map where each key is a bech32 consensus address and the value its signing info, or an error if the query fails
+func (s *Server) GetSigningInfos() (map[string]slashingtypes.ValidatorSigningInfo, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := slashingtypes.NewQueryClient(s.Conn)
+
+	result := make(map[string]slashingtypes.ValidatorSigningInfo)
+	var nextKey []byte
+	for {
+		req := &slashingtypes.QuerySigningInfosRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.SigningInfos(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query signing infos: %v", err)
+			return nil, err
+		}
+
+		for _, info := range resp.Info {
+			result[info.Address] = info
+		}
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetValidatorConsAddress resolves the consensus address of a validator from its consensus pubkey.
+//
+// @param validator the validator
+// @return the consensus address, or an error if the pubkey can't be decoded
+func GetValidatorConsAddress(validator stakingtypes.Validator) (sdk.ConsAddress, error) {
+	if err := validator.UnpackInterfaces(interfaceRegistry); err != nil {
+		log.Printf("error when unpack consensus pubkey of %v, err: %v", validator.OperatorAddress, err.Error())
+		return nil, err
+	}
+
+	return validator.GetConsAddr()
+}
+
+// GetValidatorUptime computes the share of the last window blocks signed by a validator from the block commits.
+//
+// Unlike the missed blocks counter of the signing info, which is bound to the slashing window, any
+// window can be used. Each block is fetched, so large windows are slow.
+//
+// @param validatorAddress the operator address of the validator
+// @param window the number of blocks to look back from the latest block
+// @return the uptime, or an error if a query fails
+func (s *Server) GetValidatorUptime(validatorAddress string, window int64) (*ValidatorUptime, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive, got: %v", window)
+	}
+
+	validator, err := s.GetValidator(validatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	consAddr, err := GetValidatorConsAddress(validator)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.GetLatestBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	// the commit of a block is included in the next one
+	result := &ValidatorUptime{
+		ConsensusAddress: consAddr.String(),
+		FromHeight:       latest - window,
+		ToHeight:         latest - 1,
+	}
+	if result.FromHeight < 1 {
+		result.FromHeight = 1
+	}
+
+	for height := result.FromHeight + 1; height <= latest; height++ {
+		block, err := s.GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		if block.LastCommit != nil && signedCommit(block.LastCommit, consAddr) {
+			result.SignedBlocks++
+		} else {
+			result.MissedBlocks++
+		}
+	}
+
+	result.Uptime = sdk.ZeroDec()
+	if total := result.SignedBlocks + result.MissedBlocks; total > 0 {
+		result.Uptime = sdk.NewDec(result.SignedBlocks).QuoInt64(total)
+	}
+
+	return result, nil
+}
+
+func signedCommit(commit *tmproto.Commit, consAddr sdk.ConsAddress) bool {
+	for _, sig := range commit.Signatures {
+		if sig.BlockIdFlag == tmproto.BlockIDFlagCommit && consAddr.Equals(sdk.ConsAddress(sig.ValidatorAddress)) {
+			return true
+		}
+	}
+
+	return false
+}
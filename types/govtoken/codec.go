@@ -26,6 +26,8 @@ const (
 	changeOwnerName             = "cysicmint/govtoken/MsgChangeOwner"
 	burnName                    = "cysicmint/govtoken/MsgBurn"
 	mintName                    = "cysicmint/govtoken/MsgMint"
+	stakeAsValidatorName        = "cysicmint/govtoken/MsgStakeAsValidator"
+	delegateToValidatorName     = "cysicmint/govtoken/MsgDelegateToValidator"
 )
 
 // RegisterInterfaces registers the x/cysic interfaces types with the interface registry
@@ -37,6 +39,8 @@ func RegisterInterfaces(registry codectypes.InterfaceRegistry) {
 		&MsgExchangeToGovToken{},
 		&MsgExchangeToPlatformToken{},
 		&MsgSetExchangeRate{},
+		&MsgStakeAsValidator{},
+		&MsgDelegateToValidator{},
 	)
 
 	registry.RegisterImplementations(
@@ -55,5 +59,7 @@ func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
 	cdc.RegisterConcrete(&MsgExchangeToGovToken{}, exchangeToGovTokenName, nil)
 	cdc.RegisterConcrete(&MsgExchangeToPlatformToken{}, exchangeToPlatformTokenName, nil)
 	cdc.RegisterConcrete(&MsgSetExchangeRate{}, setExchangeRateName, nil)
+	cdc.RegisterConcrete(&MsgStakeAsValidator{}, stakeAsValidatorName, nil)
+	cdc.RegisterConcrete(&MsgDelegateToValidator{}, delegateToValidatorName, nil)
 	cdc.RegisterConcrete(&Params{}, paramsName, nil)
 }
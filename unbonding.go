@@ -0,0 +1,231 @@
+package gosdk
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// UnbondingMaturity is an unbonding entry with the time left until its coins are released.
+type UnbondingMaturity struct {
+	ValidatorAddress string        `json:"validator_address"`
+	CreationHeight   int64         `json:"creation_height"`
+	CompletionTime   time.Time     `json:"completion_time"`
+	InitialBalance   math.Int      `json:"initial_balance"`
+	Balance          math.Int      `json:"balance"`
+	Remaining        time.Duration `json:"remaining"`
+	Matured          bool          `json:"matured"`
+}
+
+// RedelegateCGT moves delegated CGT tokens from one validator to another without unbonding.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param srcValidatorAddress the address of the validator to redelegate from
+// @param dstValidatorAddress the address of the validator to redelegate to
+// @param amount the amount to redelegate
+// @return the transaction hash as a string, or an error if the redelegation fails
+func (s *Server) RedelegateCGT(signer Signer, srcValidatorAddress string, dstValidatorAddress string, amount math.Int) (string, error) {
+	msg := &stakingtypes.MsgBeginRedelegate{
+		DelegatorAddress:    signer.CosmosAddr.String(),
+		ValidatorSrcAddress: srcValidatorAddress,
+		ValidatorDstAddress: dstValidatorAddress,
+		Amount: sdk.Coin{
+			Denom:  CGTToken,
+			Amount: amount,
+		},
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+}
+
+// CancelUnbondingCGT cancels an unbonding entry and delegates its CGT tokens back to the validator.
+//
+// @param signer the Signer instance used to sign the transaction
+// @param validatorAddress the address of the validator
+// @param amount the amount to cancel, at most the balance of the unbonding entry
+// @param creationHeight the creation height of the unbonding entry
+// @return the transaction hash as a string, or an error if the cancellation fails
+func (s *Server) CancelUnbondingCGT(signer Signer, validatorAddress string, amount math.Int, creationHeight int64) (string, error) {
+	msg := &stakingtypes.MsgCancelUnbondingDelegation{
+		DelegatorAddress: signer.CosmosAddr.String(),
+		ValidatorAddress: validatorAddress,
+		Amount: sdk.Coin{
+			Denom:  CGTToken,
+			Amount: amount,
+		},
+		CreationHeight: creationHeight,
+	}
+
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return "", err
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return "", err
+	}
+
+	return s.buildAndBroadcastCosmosTx(signer, []sdk.Msg{msg})
+}
+
+// QueryUnbondingDelegations retrieves the pending unbonding delegations of a delegator across all validators.
+//
+// @param delegatorAddress the address of the delegator
+// @return the unbonding delegations, each with its entries and completion times, or an error if the query fails
+func (s *Server) QueryUnbondingDelegations(delegatorAddress string) ([]stakingtypes.UnbondingDelegation, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		return nil, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+
+	result := make([]stakingtypes.UnbondingDelegation, 0)
+	var nextKey []byte
+	for {
+		req := &stakingtypes.QueryDelegatorUnbondingDelegationsRequest{
+			DelegatorAddr: targetAddr,
+			Pagination:    &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.DelegatorUnbondingDelegations(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query unbonding delegations: %v", err)
+			return nil, err
+		}
+
+		result = append(result, resp.UnbondingResponses...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// QueryRedelegations retrieves the pending redelegations of a delegator across all validators.
+//
+// @param delegatorAddress the address of the delegator
+// @return the redelegations, each with its entries and completion times, or an error if the query fails
+func (s *Server) QueryRedelegations(delegatorAddress string) ([]stakingtypes.RedelegationResponse, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	targetAddr, err := ConvertToCysicAddress(delegatorAddress)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", delegatorAddress, err.Error())
+		return nil, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+
+	result := make([]stakingtypes.RedelegationResponse, 0)
+	var nextKey []byte
+	for {
+		req := &stakingtypes.QueryRedelegationsRequest{
+			DelegatorAddr: targetAddr,
+			Pagination:    &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.Redelegations(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query redelegations: %v", err)
+			return nil, err
+		}
+
+		result = append(result, resp.RedelegationResponses...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return result, nil
+}
+
+// GetStakingParams retrieves the parameters of the staking module, e.g. the unbonding time.
+//
+// @return the staking parameters, or an error if the query fails
+func (s *Server) GetStakingParams() (*stakingtypes.Params, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+	resp, err := client.Params(s.queryContext(), &stakingtypes.QueryParamsRequest{})
+	if err != nil {
+		log.Printf("could not query staking params: %v", err)
+		return nil, err
+	}
+
+	return &resp.Params, nil
+}
+
+// GetUnbondingMaturities reports when each pending unbonding entry of a delegator matures.
+//
+// @param delegatorAddress the address of the delegator
+// @param now the time to compute the remaining durations from
+// @return the unbonding entries ordered by completion time, or an error if the query fails
+func (s *Server) GetUnbondingMaturities(delegatorAddress string, now time.Time) ([]UnbondingMaturity, error) {
+	unbondingList, err := s.QueryUnbondingDelegations(delegatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUnbondingMaturities(unbondingList, now), nil
+}
+
+// NewUnbondingMaturities flattens unbonding delegations into entries ordered by completion time.
+//
+// @param unbondingList the unbonding delegations
+// @param now the time to compute the remaining durations from
+// @return the unbonding entries
+func NewUnbondingMaturities(unbondingList []stakingtypes.UnbondingDelegation, now time.Time) []UnbondingMaturity {
+	result := make([]UnbondingMaturity, 0)
+	for _, unbonding := range unbondingList {
+		for _, entry := range unbonding.Entries {
+			remaining := entry.CompletionTime.Sub(now)
+			if remaining < 0 {
+				remaining = 0
+			}
+
+			result = append(result, UnbondingMaturity{
+				ValidatorAddress: unbonding.ValidatorAddress,
+				CreationHeight:   entry.CreationHeight,
+				CompletionTime:   entry.CompletionTime,
+				InitialBalance:   entry.InitialBalance,
+				Balance:          entry.Balance,
+				Remaining:        remaining,
+				Matured:          !entry.CompletionTime.After(now),
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CompletionTime.Before(result[j].CompletionTime)
+	})
+
+	return result
+}
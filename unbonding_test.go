@@ -0,0 +1,69 @@
+package gosdk
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func TestNewUnbondingMaturities(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(height int64, completion time.Duration, balance int64) stakingtypes.UnbondingDelegationEntry {
+		return stakingtypes.UnbondingDelegationEntry{
+			CreationHeight: height,
+			CompletionTime: now.Add(completion),
+			InitialBalance: math.NewInt(balance),
+			Balance:        math.NewInt(balance),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		unbondingList []stakingtypes.UnbondingDelegation
+		want          []UnbondingMaturity
+	}{
+		{name: "no unbondings", want: []UnbondingMaturity{}},
+		{
+			name: "ordered by completion time across validators",
+			unbondingList: []stakingtypes.UnbondingDelegation{
+				{ValidatorAddress: "valA", Entries: []stakingtypes.UnbondingDelegationEntry{entry(30, 3*time.Hour, 300), entry(10, time.Hour, 100)}},
+				{ValidatorAddress: "valB", Entries: []stakingtypes.UnbondingDelegationEntry{entry(20, 2*time.Hour, 200)}},
+			},
+			want: []UnbondingMaturity{
+				{ValidatorAddress: "valA", CreationHeight: 10, Remaining: time.Hour},
+				{ValidatorAddress: "valB", CreationHeight: 20, Remaining: 2 * time.Hour},
+				{ValidatorAddress: "valA", CreationHeight: 30, Remaining: 3 * time.Hour},
+			},
+		},
+		{
+			name: "matured entries have no remaining time",
+			unbondingList: []stakingtypes.UnbondingDelegation{
+				{ValidatorAddress: "valA", Entries: []stakingtypes.UnbondingDelegationEntry{entry(20, 0, 200), entry(10, -time.Hour, 100), entry(30, time.Minute, 300)}},
+			},
+			want: []UnbondingMaturity{
+				{ValidatorAddress: "valA", CreationHeight: 10, Matured: true},
+				{ValidatorAddress: "valA", CreationHeight: 20, Matured: true},
+				{ValidatorAddress: "valA", CreationHeight: 30, Remaining: time.Minute},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewUnbondingMaturities(tt.unbondingList, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("NewUnbondingMaturities() = %+v, want %+v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].ValidatorAddress != want.ValidatorAddress || got[i].CreationHeight != want.CreationHeight ||
+					got[i].Remaining != want.Remaining || got[i].Matured != want.Matured {
+					t.Errorf("entry %d = %+v, want %+v", i, got[i], want)
+				}
+				if !got[i].Balance.Equal(math.NewInt(got[i].CreationHeight * 10)) {
+					t.Errorf("entry %d balance = %v, want %v", i, got[i].Balance, got[i].CreationHeight*10)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package gosdk
 
 import (
-	"context"
 	"log"
+	"time"
 
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetValidator retrieves a validator by address.
@@ -27,7 +32,7 @@ func (s *Server) GetValidator(addr string) (stakingtypes.Validator, error) {
 	req := &stakingtypes.QueryValidatorRequest{ValidatorAddr: addr}
 
 	opt := make([]grpc.CallOption, 0)
-	resp, err := client.Validator(context.Background(), req, opt...)
+	resp, err := client.Validator(s.queryContext(), req, opt...)
 	if err != nil {
 		log.Printf("could not query balances: %v", err)
 		return result, err
@@ -60,7 +65,7 @@ func (s *Server) GetValidatorList(offset uint64, pageSize uint64) ([]stakingtype
 	}
 
 	opt := make([]grpc.CallOption, 0)
-	resp, err := client.Validators(context.Background(), req, opt...)
+	resp, err := client.Validators(s.queryContext(), req, opt...)
 	if err != nil {
 		log.Printf("could not query balances: %v", err)
 		return nil, 0, err
@@ -68,3 +73,331 @@ func (s *Server) GetValidatorList(offset uint64, pageSize uint64) ([]stakingtype
 
 	return resp.Validators, resp.Pagination.Total, nil
 }
+
+// GetAllValidators retrieves every validator, whatever its status.
+//
+// @return the list of validators, or an error if retrieval fails
+func (s *Server) GetAllValidators() ([]stakingtypes.Validator, error) {
+	return s.GetValidatorsByStatus("")
+}
+
+// GetValidatorsByStatus retrieves every validator with a given status.
+//
+// @param bondStatus the bond status, stakingtypes.BondStatusBonded, BondStatusUnbonding, BondStatusUnbonded, or empty for all
+// @return the list of validators, or an error if retrieval fails
+func (s *Server) GetValidatorsByStatus(bondStatus string) ([]stakingtypes.Validator, error) {
+	result := make([]stakingtypes.Validator, 0)
+	err := s.IterateValidators(bondStatus, func(validator stakingtypes.Validator) error {
+		result = append(result, validator)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetValidatorListByStatus retrieves a page of validators with a given status.
+//
+// @param bondStatus the bond status, stakingtypes.BondStatusBonded, BondStatusUnbonding, BondStatusUnbonded, or empty for all
+// @param offset the offset for pagination
+// @param pageSize the page size for pagination
+// @return a list of validators, the total count, or an error if retrieval fails
+func (s *Server) GetValidatorListByStatus(bondStatus string, offset uint64, pageSize uint64) ([]stakingtypes.Validator, uint64, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, 0, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+
+	req := &stakingtypes.QueryValidatorsRequest{
+		Status: bondStatus,
+		Pagination: &query.PageRequest{
+			Offset:     offset,
+			Limit:      pageSize,
+			CountTotal: true,
+		},
+	}
+	resp, err := client.Validators(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query validators: %v", err)
+		return nil, 0, err
+	}
+
+	return resp.Validators, resp.Pagination.Total, nil
+}
+
+// IterateValidators calls fn for every validator with a given status, page by page.
+//
+// @param bondStatus the bond status, stakingtypes.BondStatusBonded, BondStatusUnbonding, BondStatusUnbonded, or empty for all
+// @param fn the function called for each validator, returning an error stops the iteration
+// @return the error returned by fn, or an error if retrieval fails
+func (s *Server) IterateValidators(bondStatus string, fn func(validator stakingtypes.Validator) error) error {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+
+	var nextKey []byte
+	for {
+		req := &stakingtypes.QueryValidatorsRequest{
+			Status:     bondStatus,
+			Pagination: &query.PageRequest{Key: nextKey},
+		}
+		resp, err := client.Validators(s.queryContext(), req)
+		if err != nil {
+			log.Printf("could not query validators: %v", err)
+			return err
+		}
+
+		for _, validator := range resp.Validators {
+			if err := fn(validator); err != nil {
+				return err
+			}
+		}
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return nil
+}
+
+// ValidatorInfo is a validator joined with its voting power share, delegations and signing info.
+type ValidatorInfo struct {
+	OperatorAddress         string    `json:"operator_address"`
+	ConsensusAddress        string    `json:"consensus_address"`
+	Moniker                 string    `json:"moniker"`
+	Status                  string    `json:"status"`
+	Jailed                  bool      `json:"jailed"`
+	Tokens                  math.Int  `json:"tokens"`
+	VotingPowerShare        sdk.Dec   `json:"voting_power_share"`
+	CommissionRate          sdk.Dec   `json:"commission_rate"`
+	CommissionMaxRate       sdk.Dec   `json:"commission_max_rate"`
+	CommissionMaxChangeRate sdk.Dec   `json:"commission_max_change_rate"`
+	CommissionUpdateTime    time.Time `json:"commission_update_time"`
+	MinSelfDelegation       math.Int  `json:"min_self_delegation"`
+	SelfDelegation          math.Int  `json:"self_delegation"`
+	DelegatorCount          uint64    `json:"delegator_count"`
+	MissedBlocks            int64     `json:"missed_blocks"`
+	SignedBlocksWindow      int64     `json:"signed_blocks_window"`
+	Uptime                  sdk.Dec   `json:"uptime"`
+	JailedUntil             time.Time `json:"jailed_until"`
+	Tombstoned              bool      `json:"tombstoned"`
+}
+
+// GetStakingPool retrieves the bonded and not bonded token pools.
+//
+// @return the staking pool, or an error if the query fails
+func (s *Server) GetStakingPool() (*stakingtypes.Pool, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return nil, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+	resp, err := client.Pool(s.queryContext(), &stakingtypes.QueryPoolRequest{})
+	if err != nil {
+		log.Printf("could not query staking pool: %v", err)
+		return nil, err
+	}
+
+	return &resp.Pool, nil
+}
+
+// GetSelfDelegation retrieves the tokens the operator of a validator delegated to it.
+//
+// @param validatorAddress the operator address of the validator
+// @return the self-delegated tokens, zero if the operator has no delegation, or an error if the query fails
+func (s *Server) GetSelfDelegation(validatorAddress string) (math.Int, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return math.Int{}, err
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(validatorAddress)
+	if err != nil {
+		log.Printf("error when convert validator addr: %v, err: %v", validatorAddress, err.Error())
+		return math.Int{}, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+	req := &stakingtypes.QueryDelegationRequest{
+		DelegatorAddr: sdk.AccAddress(valAddr).String(),
+		ValidatorAddr: validatorAddress,
+	}
+	resp, err := client.Delegation(s.queryContext(), req)
+	if status.Code(err) == codes.NotFound {
+		return math.ZeroInt(), nil
+	}
+	if err != nil {
+		log.Printf("could not query self delegation of %v: %v", validatorAddress, err)
+		return math.Int{}, err
+	}
+
+	return resp.DelegationResponse.Balance.Amount, nil
+}
+
+// GetValidatorDelegatorCount retrieves the number of delegators of a validator.
+//
+// @param validatorAddress the operator address of the validator
+// @return the number of delegators, or an error if the query fails
+func (s *Server) GetValidatorDelegatorCount(validatorAddress string) (uint64, error) {
+	if err := s.KeepGrpcConn(); err != nil {
+		log.Printf("error when keep grpc conn, endpoint: %v, err: %v", s.EndPoint, err.Error())
+		return 0, err
+	}
+
+	client := stakingtypes.NewQueryClient(s.Conn)
+	req := &stakingtypes.QueryValidatorDelegationsRequest{
+		ValidatorAddr: validatorAddress,
+		Pagination:    &query.PageRequest{Limit: 1, CountTotal: true},
+	}
+	resp, err := client.ValidatorDelegations(s.queryContext(), req)
+	if err != nil {
+		log.Printf("could not query delegations of %v: %v", validatorAddress, err)
+		return 0, err
+	}
+	if resp.Pagination == nil {
+		return uint64(len(resp.DelegationResponses)), nil
+	}
+
+	return resp.Pagination.Total, nil
+}
+
+// GetValidatorInfo retrieves a validator with its voting power share, delegations and signing info.
+//
+// @param validatorAddress the operator address of the validator
+// @return the validator info, or an error if a query fails
+func (s *Server) GetValidatorInfo(validatorAddress string) (*ValidatorInfo, error) {
+	validator, err := s.GetValidator(validatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := s.GetStakingPool()
+	if err != nil {
+		return nil, err
+	}
+	params, err := s.GetSlashingParams()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.newValidatorInfo(validator, pool.BondedTokens, params.SignedBlocksWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	signingInfo, err := s.GetSigningInfo(info.ConsensusAddress)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	if signingInfo != nil {
+		setSigningInfo(info, *signingInfo)
+	}
+
+	return info, nil
+}
+
+// GetValidatorInfoList retrieves every validator with a given status, with its voting power share, delegations and signing info.
+//
+// @param bondStatus the bond status, stakingtypes.BondStatusBonded, BondStatusUnbonding, BondStatusUnbonded, or empty for all
+// @return the list of validator infos, or an error if a query fails
+func (s *Server) GetValidatorInfoList(bondStatus string) ([]*ValidatorInfo, error) {
+	validatorList, err := s.GetValidatorsByStatus(bondStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := s.GetStakingPool()
+	if err != nil {
+		return nil, err
+	}
+	params, err := s.GetSlashingParams()
+	if err != nil {
+		return nil, err
+	}
+	signingInfos, err := s.GetSigningInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ValidatorInfo, 0, len(validatorList))
+	for _, validator := range validatorList {
+		info, err := s.newValidatorInfo(validator, pool.BondedTokens, params.SignedBlocksWindow)
+		if err != nil {
+			return nil, err
+		}
+		if signingInfo, ok := signingInfos[info.ConsensusAddress]; ok {
+			setSigningInfo(info, signingInfo)
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+func (s *Server) newValidatorInfo(validator stakingtypes.Validator, bondedTokens math.Int, signedBlocksWindow int64) (*ValidatorInfo, error) {
+	info, err := validatorInfoOf(validator, bondedTokens, signedBlocksWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	info.SelfDelegation, err = s.GetSelfDelegation(validator.OperatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	info.DelegatorCount, err = s.GetValidatorDelegatorCount(validator.OperatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// validatorInfoOf builds the info of a validator from its state alone, without its delegations.
+func validatorInfoOf(validator stakingtypes.Validator, bondedTokens math.Int, signedBlocksWindow int64) (*ValidatorInfo, error) {
+	consAddr, err := GetValidatorConsAddress(validator)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ValidatorInfo{
+		OperatorAddress:         validator.OperatorAddress,
+		ConsensusAddress:        consAddr.String(),
+		Moniker:                 validator.Description.Moniker,
+		Status:                  validator.Status.String(),
+		Jailed:                  validator.Jailed,
+		Tokens:                  validator.Tokens,
+		VotingPowerShare:        sdk.ZeroDec(),
+		CommissionRate:          validator.Commission.Rate,
+		CommissionMaxRate:       validator.Commission.MaxRate,
+		CommissionMaxChangeRate: validator.Commission.MaxChangeRate,
+		CommissionUpdateTime:    validator.Commission.UpdateTime,
+		MinSelfDelegation:       validator.MinSelfDelegation,
+		SignedBlocksWindow:      signedBlocksWindow,
+		Uptime:                  sdk.OneDec(),
+	}
+	// only bonded validators hold voting power
+	if validator.IsBonded() && bondedTokens.IsPositive() {
+		info.VotingPowerShare = sdk.NewDecFromInt(validator.Tokens).QuoInt(bondedTokens)
+	}
+
+	return info, nil
+}
+
+func setSigningInfo(info *ValidatorInfo, signingInfo slashingtypes.ValidatorSigningInfo) {
+	info.MissedBlocks = signingInfo.MissedBlocksCounter
+	info.JailedUntil = signingInfo.JailedUntil
+	info.Tombstoned = signingInfo.Tombstoned
+	if info.SignedBlocksWindow > 0 {
+		missed := sdk.NewDec(signingInfo.MissedBlocksCounter).QuoInt64(info.SignedBlocksWindow)
+		info.Uptime = sdk.OneDec().Sub(missed)
+	}
+}
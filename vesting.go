@@ -0,0 +1,186 @@
+package gosdk
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	vestexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+)
+
+// Vesting account types
+const (
+	VestingTypeContinuous      = "continuous"
+	VestingTypeDelayed         = "delayed"
+	VestingTypePeriodic        = "periodic"
+	VestingTypePermanentLocked = "permanent_locked"
+)
+
+// VestingUnlock is a discrete unlock of a vesting schedule.
+type VestingUnlock struct {
+	Time   time.Time `json:"time"`
+	Amount sdk.Coins `json:"amount"`
+}
+
+// VestingInfo is the state of a vesting account at a given time.
+type VestingInfo struct {
+	Address          string    `json:"address"`
+	Type             string    `json:"type"`
+	At               time.Time `json:"at"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	OriginalVesting  sdk.Coins `json:"original_vesting"`
+	Vested           sdk.Coins `json:"vested"`
+	Unvested         sdk.Coins `json:"unvested"`
+	Locked           sdk.Coins `json:"locked"`
+	DelegatedVesting sdk.Coins `json:"delegated_vesting"`
+	DelegatedFree    sdk.Coins `json:"delegated_free"`
+	// Schedule lists the discrete unlocks of delayed and periodic accounts, continuous accounts unlock linearly
+	// from StartTime to EndTime and permanently locked accounts never unlock
+	Schedule []VestingUnlock `json:"schedule,omitempty"`
+}
+
+// CreateContinuousVestingAccount creates a new account whose coins vest linearly from the block time until endTime.
+//
+// @param signer the Signer instance funding the account
+// @param toAddrStr the address of the new vesting account
+// @param amount the coins to vest
+// @param endTime the time the coins are fully vested
+// @return the transaction hash as a string, or an error if the creation fails
+func (s *Server) CreateContinuousVestingAccount(signer Signer, toAddrStr string, amount sdk.Coins, endTime time.Time) (string, error) {
+	return s.createVestingAccount(signer, toAddrStr, amount, endTime, false)
+}
+
+// CreateDelayedVestingAccount creates a new account whose coins vest all at once at endTime.
+//
+// @param signer the Signer instance funding the account
+// @param toAddrStr the address of the new vesting account
+// @param amount the coins to vest
+// @param endTime the time the coins are vested
+// @return the transaction hash as a string, or an error if the creation fails
+func (s *Server) CreateDelayedVestingAccount(signer Signer, toAddrStr string, amount sdk.Coins, endTime time.Time) (string, error) {
+	return s.createVestingAccount(signer, toAddrStr, amount, endTime, true)
+}
+
+// CreatePeriodicVestingAccount creates a new account whose coins vest at the end of each period.
+//
+// @param signer the Signer instance funding the account
+// @param toAddrStr the address of the new vesting account
+// @param startTime the start of the first period
+// @param periods the vesting periods, each with its length in seconds and the coins vesting at its end
+// @return the transaction hash as a string, or an error if the creation fails
+func (s *Server) CreatePeriodicVestingAccount(signer Signer, toAddrStr string, startTime time.Time, periods []vestingtypes.Period) (string, error) {
+	toAddr, err := toAccAddress(toAddrStr)
+	if err != nil {
+		return "", err
+	}
+
+	msg := vestingtypes.NewMsgCreatePeriodicVestingAccount(signer.CosmosAddr, toAddr, startTime.Unix(), periods)
+	return s.broadcastMsg(signer, msg)
+}
+
+func (s *Server) createVestingAccount(signer Signer, toAddrStr string, amount sdk.Coins, endTime time.Time, delayed bool) (string, error) {
+	toAddr, err := toAccAddress(toAddrStr)
+	if err != nil {
+		return "", err
+	}
+
+	msg := vestingtypes.NewMsgCreateVestingAccount(signer.CosmosAddr, toAddr, amount, endTime.Unix(), delayed)
+	return s.broadcastMsg(signer, msg)
+}
+
+// GetVestingAccount retrieves a vesting account.
+//
+// @param addr the address of the vesting account
+// @return the vesting account, or an error if the account does not exist or is not a vesting account
+func (s *Server) GetVestingAccount(addr string) (vestexported.VestingAccount, error) {
+	account, err := s.GetAccountI(addr)
+	if err != nil {
+		log.Printf("error when GetAccountI: %v, err: %v", addr, err.Error())
+		return nil, err
+	}
+
+	vestingAccount, ok := account.(vestexported.VestingAccount)
+	if !ok {
+		return nil, fmt.Errorf("account %v is %T, not a vesting account", account.GetAddress().String(), account)
+	}
+
+	return vestingAccount, nil
+}
+
+// GetVestingInfo retrieves the vested, unvested, locked and delegated amounts of a vesting account at a given time.
+//
+// @param addr the address of the vesting account
+// @param at the time to compute the amounts at
+// @return the vesting info, or an error if the account is not a vesting account
+func (s *Server) GetVestingInfo(addr string, at time.Time) (*VestingInfo, error) {
+	account, err := s.GetVestingAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVestingInfo(account, at), nil
+}
+
+// NewVestingInfo computes the state of a vesting account at a given time.
+//
+// @param account the vesting account
+// @param at the time to compute the amounts at
+// @return the vesting info
+func NewVestingInfo(account vestexported.VestingAccount, at time.Time) *VestingInfo {
+	info := &VestingInfo{
+		Address:          account.GetAddress().String(),
+		At:               at,
+		StartTime:        time.Unix(account.GetStartTime(), 0).UTC(),
+		EndTime:          time.Unix(account.GetEndTime(), 0).UTC(),
+		OriginalVesting:  account.GetOriginalVesting(),
+		Vested:           account.GetVestedCoins(at),
+		Unvested:         account.GetVestingCoins(at),
+		Locked:           account.LockedCoins(at),
+		DelegatedVesting: account.GetDelegatedVesting(),
+		DelegatedFree:    account.GetDelegatedFree(),
+	}
+
+	switch acc := account.(type) {
+	case *vestingtypes.ContinuousVestingAccount:
+		info.Type = VestingTypeContinuous
+	case *vestingtypes.DelayedVestingAccount:
+		info.Type = VestingTypeDelayed
+		info.Schedule = []VestingUnlock{{Time: info.EndTime, Amount: acc.OriginalVesting}}
+	case *vestingtypes.PeriodicVestingAccount:
+		info.Type = VestingTypePeriodic
+		unlockTime := acc.StartTime
+		for _, period := range acc.VestingPeriods {
+			unlockTime += period.Length
+			info.Schedule = append(info.Schedule, VestingUnlock{
+				Time:   time.Unix(unlockTime, 0).UTC(),
+				Amount: period.Amount,
+			})
+		}
+	case *vestingtypes.PermanentLockedAccount:
+		info.Type = VestingTypePermanentLocked
+	default:
+		info.Type = fmt.Sprintf("%T", account)
+	}
+
+	return info
+}
+
+// toAccAddress converts a 0x or cysic address to an AccAddress.
+func toAccAddress(addr string) (sdk.AccAddress, error) {
+	cysicAddr, err := ConvertToCysicAddress(addr)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", addr, err.Error())
+		return nil, err
+	}
+
+	accAddr, err := sdk.AccAddressFromBech32(cysicAddr)
+	if err != nil {
+		log.Printf("error when convert addr: %v to accAddr, err: %v", cysicAddr, err.Error())
+		return nil, err
+	}
+
+	return accAddr, nil
+}
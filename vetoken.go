@@ -0,0 +1,304 @@
+package gosdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	delegatetypes "github.com/hack2fun/gosdk/types/delegate"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// VeToken bind actions
+const (
+	VeTokenActionKept    = "kept"
+	VeTokenActionBound   = "bound"
+	VeTokenActionRebound = "rebound"
+	VeTokenActionMoved   = "moved"
+	VeTokenActionUpdated = "updated"
+)
+
+const (
+	defaultVeTokenPollInterval  = BlockTime
+	defaultVeTokenRetryInterval = 10 * BlockTime
+	maxVeTokenRetryShift        = 6
+)
+
+// VeTokenTarget is the validator and amount a veToken should be bound to.
+type VeTokenTarget struct {
+	Validator string   `json:"validator"`
+	Amount    math.Int `json:"amount"`
+}
+
+// VeTokenManagerConfig configures a VeTokenManager.
+type VeTokenManagerConfig struct {
+	// Epoch describes the epochs of the delegate module
+	Epoch EpochConfig
+	// Targets maps each veToken to its target bind
+	Targets map[string]VeTokenTarget
+	// PollInterval is how often the binds are checked
+	PollInterval time.Duration
+	// TxTimeout is how long to wait for the delegate transaction to be packed
+	TxTimeout time.Duration
+	// RetryInterval is how long a bind still differing after a delegate transaction waits before it is
+	// submitted again in the same epoch, doubled after every attempt
+	RetryInterval time.Duration
+	// OnReport is called with the report of every check, optional
+	OnReport func(report *VeTokenEpochReport)
+}
+
+// VeTokenBindOutcome is the outcome of checking the bind of one veToken.
+type VeTokenBindOutcome struct {
+	Token             string `json:"token"`
+	Validator         string `json:"validator"`
+	Amount            string `json:"amount"`
+	PreviousValidator string `json:"previous_validator,omitempty"`
+	PreviousAmount    string `json:"previous_amount,omitempty"`
+	Action            string `json:"action"`
+	TxHash            string `json:"tx_hash,omitempty"`
+	Success           bool   `json:"success"`
+	Error             string `json:"error,omitempty"`
+	// RetryAt is set when the delegate transaction is deferred because an earlier attempt in the epoch is backing off
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
+// VeTokenEpochReport is the report of one check of the binds of a worker.
+type VeTokenEpochReport struct {
+	Epoch    int64                `json:"epoch"`
+	Worker   string               `json:"worker"`
+	NewEpoch bool                 `json:"new_epoch"`
+	Time     time.Time            `json:"time"`
+	Outcomes []VeTokenBindOutcome `json:"outcomes"`
+}
+
+// VeTokenManager keeps the veTokens of a worker bound to their target validators across epochs.
+//
+// Binds are kept per epoch by the delegate module, so each check compares the bind of every veToken
+// in the current epoch with its target and submits MsgDelegate for the ones that are missing or differ.
+// A veToken whose bind still differs after a delegate transaction is retried with a backoff, and
+// right away in the next epoch, so that a bind the chain keeps rejecting doesn't pay a fee every poll.
+type VeTokenManager struct {
+	server    *Server
+	signer    Signer
+	config    VeTokenManagerConfig
+	lastEpoch int64
+	lastBinds map[string]*DelegateBind
+	attempts  map[string]*veTokenAttempt
+}
+
+// veTokenAttempt records the delegate transactions submitted for a veToken in an epoch.
+type veTokenAttempt struct {
+	epoch int64
+	count int
+	next  time.Time
+}
+
+// NewVeTokenManager creates a new VeTokenManager for a worker.
+//
+// @param signer the Signer instance of the worker
+// @param config the manager configuration
+// @return a new VeTokenManager instance, or an error if the config is invalid
+func (s *Server) NewVeTokenManager(signer Signer, config VeTokenManagerConfig) (*VeTokenManager, error) {
+	if config.Epoch.EpochLength <= 0 {
+		return nil, fmt.Errorf("epoch length must be positive, got: %v", config.Epoch.EpochLength)
+	}
+	for token, target := range config.Targets {
+		if target.Validator == "" {
+			return nil, fmt.Errorf("target validator of %v is empty", token)
+		}
+		if target.Amount.IsNil() || !target.Amount.IsPositive() {
+			return nil, fmt.Errorf("target amount of %v must be positive", token)
+		}
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultVeTokenPollInterval
+	}
+	if config.TxTimeout <= 0 {
+		config.TxTimeout = defaultTxWaitTimeout
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultVeTokenRetryInterval
+	}
+
+	return &VeTokenManager{
+		server:    s,
+		signer:    signer,
+		config:    config,
+		lastEpoch: -1,
+		lastBinds: make(map[string]*DelegateBind),
+		attempts:  make(map[string]*veTokenAttempt),
+	}, nil
+}
+
+// Run checks the binds every PollInterval until ctx is cancelled.
+//
+// @param ctx the context controlling the manager's lifetime
+// @return the context error once the manager stops
+func (m *VeTokenManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.RunOnce(); err != nil {
+			log.Printf("error when check veToken binds of %v, err: %v", m.signer.EthAddr.String(), err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce checks the binds of the current epoch and delegates the veTokens whose bind is missing or differs from the target.
+// VeTokens already submitted in the epoch are deferred until their retry time, see RetryInterval.
+//
+// @return the report of the check, or an error if the epoch or a bind can't be queried, or the delegate transaction fails
+func (m *VeTokenManager) RunOnce() (*VeTokenEpochReport, error) {
+	epoch, err := m.server.GetCurrentEpoch(m.config.Epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VeTokenEpochReport{
+		Epoch:    epoch,
+		Worker:   m.signer.EthAddr.String(),
+		NewEpoch: epoch != m.lastEpoch,
+		Time:     time.Now(),
+		Outcomes: make([]VeTokenBindOutcome, 0, len(m.config.Targets)),
+	}
+
+	msgList := make([]sdk.Msg, 0)
+	msgOutcomes := make([]int, 0)
+	for _, token := range sortedKeys(m.config.Targets) {
+		target := m.config.Targets[token]
+		outcome := VeTokenBindOutcome{
+			Token:     token,
+			Validator: target.Validator,
+			Amount:    target.Amount.String(),
+		}
+
+		bind, err := m.server.QueryDelegateBind(epoch, report.Worker, token)
+		if err != nil {
+			return nil, err
+		}
+		outcome.PreviousValidator = bind.Validator
+		outcome.PreviousAmount = bind.Amount
+		outcome.Action = m.bindAction(token, bind, target)
+
+		if outcome.Action == VeTokenActionKept {
+			outcome.Success = true
+			delete(m.attempts, token)
+		} else if retryAt, deferred := m.retryAt(token, epoch, report.Time); deferred {
+			outcome.RetryAt = &retryAt
+		} else {
+			m.recordAttempt(token, epoch, report.Time)
+			msgList = append(msgList, &delegatetypes.MsgDelegate{
+				Worker:    report.Worker,
+				Validator: target.Validator,
+				Token:     token,
+				Amount:    target.Amount.String(),
+			})
+			msgOutcomes = append(msgOutcomes, len(report.Outcomes))
+		}
+		report.Outcomes = append(report.Outcomes, outcome)
+		m.lastBinds[token] = bind
+	}
+	m.lastEpoch = epoch
+
+	if len(msgList) != 0 {
+		err = m.delegate(report, msgList, msgOutcomes)
+	}
+
+	if m.config.OnReport != nil {
+		m.config.OnReport(report)
+	}
+
+	return report, err
+}
+
+// bindAction decides what to do with the current bind of a veToken.
+func (m *VeTokenManager) bindAction(token string, bind *DelegateBind, target VeTokenTarget) string {
+	if bind.Validator == "" {
+		// a bind that existed at the previous check and is gone now was dropped
+		if last, ok := m.lastBinds[token]; ok && last.Validator != "" {
+			return VeTokenActionRebound
+		}
+		return VeTokenActionBound
+	}
+	if bind.Validator != target.Validator {
+		return VeTokenActionMoved
+	}
+	if amount, ok := math.NewIntFromString(bind.Amount); !ok || !amount.Equal(target.Amount) {
+		return VeTokenActionUpdated
+	}
+
+	return VeTokenActionKept
+}
+
+// retryAt returns when the bind of a veToken may be submitted again, deferred is false when it may be submitted now.
+func (m *VeTokenManager) retryAt(token string, epoch int64, now time.Time) (time.Time, bool) {
+	attempt, ok := m.attempts[token]
+	if !ok || attempt.epoch != epoch || !now.Before(attempt.next) {
+		return time.Time{}, false
+	}
+
+	return attempt.next, true
+}
+
+// recordAttempt records a delegate transaction for a veToken and schedules its next attempt in the epoch.
+func (m *VeTokenManager) recordAttempt(token string, epoch int64, now time.Time) {
+	attempt, ok := m.attempts[token]
+	if !ok || attempt.epoch != epoch {
+		attempt = &veTokenAttempt{epoch: epoch}
+		m.attempts[token] = attempt
+	}
+
+	shift := attempt.count
+	if shift > maxVeTokenRetryShift {
+		shift = maxVeTokenRetryShift
+	}
+	attempt.count++
+	attempt.next = now.Add(m.config.RetryInterval << shift)
+}
+
+// delegate submits the delegate messages in one transaction and records the success flag of each response.
+func (m *VeTokenManager) delegate(report *VeTokenEpochReport, msgList []sdk.Msg, msgOutcomes []int) error {
+	fail := func(err error) error {
+		for _, index := range msgOutcomes {
+			report.Outcomes[index].Error = err.Error()
+		}
+		return err
+	}
+
+	txHash, err := m.server.buildAndBroadcastSimulatedCosmosTx(m.signer, msgList)
+	if err != nil {
+		return fail(err)
+	}
+	for _, index := range msgOutcomes {
+		report.Outcomes[index].TxHash = txHash
+	}
+
+	txResp, err := m.server.WaitTx(txHash, m.config.TxTimeout)
+	if err != nil {
+		return fail(err)
+	}
+	if txResp.Code != 0 {
+		return fail(fmt.Errorf("tx %v failed, log: %v", txHash, txResp.RawLog))
+	}
+
+	for i, index := range msgOutcomes {
+		var resp delegatetypes.MsgDelegateResponse
+		if err := DecodeMsgResponse(txResp, i, &resp); err != nil {
+			report.Outcomes[index].Error = err.Error()
+			continue
+		}
+		report.Outcomes[index].Success = resp.Success
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package gosdk
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+)
+
+func TestVeTokenBindAction(t *testing.T) {
+	target := VeTokenTarget{Validator: "valA", Amount: math.NewInt(100)}
+
+	tests := []struct {
+		name string
+		last *DelegateBind
+		bind *DelegateBind
+		want string
+	}{
+		{name: "never bound", bind: &DelegateBind{}, want: VeTokenActionBound},
+		{name: "still unbound", last: &DelegateBind{}, bind: &DelegateBind{}, want: VeTokenActionBound},
+		{name: "bind dropped", last: &DelegateBind{Validator: "valA", Amount: "100"}, bind: &DelegateBind{}, want: VeTokenActionRebound},
+		{name: "bound elsewhere", bind: &DelegateBind{Validator: "valB", Amount: "100"}, want: VeTokenActionMoved},
+		{name: "different amount", bind: &DelegateBind{Validator: "valA", Amount: "50"}, want: VeTokenActionUpdated},
+		{name: "invalid amount", bind: &DelegateBind{Validator: "valA", Amount: "invalid"}, want: VeTokenActionUpdated},
+		{name: "on target", last: &DelegateBind{Validator: "valA", Amount: "100"}, bind: &DelegateBind{Validator: "valA", Amount: "100"}, want: VeTokenActionKept},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &VeTokenManager{lastBinds: make(map[string]*DelegateBind)}
+			if tt.last != nil {
+				manager.lastBinds["veCYS"] = tt.last
+			}
+
+			if got := manager.bindAction("veCYS", tt.bind, target); got != tt.want {
+				t.Errorf("bindAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVeTokenRetryBackoff(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := time.Minute
+
+	tests := []struct {
+		name      string
+		attempts  []time.Duration
+		epoch     int64
+		at        time.Duration
+		wantRetry time.Duration
+		deferred  bool
+	}{
+		{name: "never attempted", epoch: 1, at: 0},
+		{name: "within the first backoff", attempts: []time.Duration{0}, epoch: 1, at: 30 * time.Second, wantRetry: time.Minute, deferred: true},
+		{name: "first backoff elapsed", attempts: []time.Duration{0}, epoch: 1, at: time.Minute},
+		{name: "backoff doubles", attempts: []time.Duration{0, time.Minute}, epoch: 1, at: 2 * time.Minute, wantRetry: 3 * time.Minute, deferred: true},
+		{name: "backoff is capped", attempts: []time.Duration{0, 0, 0, 0, 0, 0, 0, 0, 0}, epoch: 1, at: time.Hour, wantRetry: 64 * time.Minute, deferred: true},
+		{name: "next epoch retries right away", attempts: []time.Duration{0}, epoch: 2, at: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &VeTokenManager{
+				config:   VeTokenManagerConfig{RetryInterval: interval},
+				attempts: make(map[string]*veTokenAttempt),
+			}
+			for _, at := range tt.attempts {
+				manager.recordAttempt("veCYS", 1, start.Add(at))
+			}
+
+			retryAt, deferred := manager.retryAt("veCYS", tt.epoch, start.Add(tt.at))
+			if deferred != tt.deferred {
+				t.Fatalf("retryAt() deferred = %v, want %v", deferred, tt.deferred)
+			}
+			if deferred && !retryAt.Equal(start.Add(tt.wantRetry)) {
+				t.Errorf("retryAt() = %v, want %v", retryAt, start.Add(tt.wantRetry))
+			}
+		})
+	}
+}
@@ -0,0 +1,542 @@
+package gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultWatcherPollInterval = BlockTime
+	defaultWatcherBufferSize   = 1024
+	watcherSubscriber          = "gosdk-deposit-watcher"
+)
+
+// Deposit is a payment received by a watched address.
+type Deposit struct {
+	TxHash   string      `json:"tx_hash"`
+	Height   int64       `json:"height"`
+	MsgIndex int         `json:"msg_index"`
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Denom    string      `json:"denom"`
+	Amount   sdkmath.Int `json:"amount"`
+}
+
+// HeightStore persists the last block height processed by a DepositWatcher.
+type HeightStore interface {
+	// LoadHeight returns the last processed height, or 0 if nothing was processed yet.
+	LoadHeight() (int64, error)
+	// SaveHeight records height as processed.
+	SaveHeight(height int64) error
+}
+
+// FileHeightStore is a HeightStore keeping the height in a plain text file.
+type FileHeightStore struct {
+	Path string
+}
+
+// NewFileHeightStore creates a new HeightStore backed by a file.
+//
+// @param path the path of the file
+// @return a new FileHeightStore instance
+func NewFileHeightStore(path string) *FileHeightStore {
+	return &FileHeightStore{Path: path}
+}
+
+// LoadHeight reads the last processed height from the file.
+//
+// @return the height, 0 if the file does not exist, or an error if the file is malformed
+func (f *FileHeightStore) LoadHeight() (int64, error) {
+	bz, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(bz)), 10, 64)
+}
+
+// SaveHeight writes the last processed height to the file.
+//
+// @param height the processed height
+// @return an error if the file can't be written
+func (f *FileHeightStore) SaveHeight(height int64) error {
+	tmpPath := f.Path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatInt(height, 10)), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, f.Path)
+}
+
+// DepositWatcherConfig configures a DepositWatcher.
+type DepositWatcherConfig struct {
+	// RPCEndpoint is the Tendermint RPC endpoint used to subscribe to new blocks and to read block results
+	// from nodes with tx indexing disabled, e.g. tcp://127.0.0.1:26657. When empty or unreachable, the
+	// watcher only polls.
+	RPCEndpoint string
+	// PollInterval is how often the latest height is polled
+	PollInterval time.Duration
+	// StartHeight is the first height to process when HeightStore holds no height, 0 means the latest block
+	StartHeight int64
+	// HeightStore persists the last processed height, optional
+	HeightStore HeightStore
+	// BufferSize is the capacity of the deposit channel
+	BufferSize int
+}
+
+// DepositWatcher detects incoming payments to a set of watched addresses.
+//
+// Every block is decoded from the transfer and coin_received events of its transactions. The txs
+// of a block are read from the tx index of the gRPC node, or, when the node doesn't index txs, from
+// GetBlockByHeight and the block results of RPCEndpoint.
+//
+// Every deposit read from Deposits must be passed to Ack once it is handled. The height of a block
+// is saved only after all of its deposits are acknowledged, and the next block is processed only
+// then, so after a restart every unacknowledged deposit is delivered again. Consumers should
+// deduplicate by TxHash, MsgIndex, To and Denom.
+type DepositWatcher struct {
+	server    *Server
+	config    DepositWatcherConfig
+	lock      sync.RWMutex
+	addresses map[string]bool
+	deposits  chan Deposit
+	rpc       *rpchttp.HTTP
+
+	// ackLock guards the deposits of ackHeight that are not acknowledged yet
+	ackLock   sync.Mutex
+	ackHeight int64
+	unacked   int
+	acked     chan struct{}
+}
+
+// NewDepositWatcher creates a new DepositWatcher for the given addresses.
+//
+// @param addresses the 0x or cysic addresses to watch
+// @param config the watcher configuration
+// @return a new DepositWatcher instance, or an error if an address is invalid
+func (s *Server) NewDepositWatcher(addresses []string, config DepositWatcherConfig) (*DepositWatcher, error) {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultWatcherPollInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultWatcherBufferSize
+	}
+
+	w := &DepositWatcher{
+		server:    s,
+		config:    config,
+		addresses: make(map[string]bool),
+		deposits:  make(chan Deposit, config.BufferSize),
+		acked:     make(chan struct{}, 1),
+	}
+	if config.RPCEndpoint != "" {
+		client, err := rpchttp.New(config.RPCEndpoint, "/websocket")
+		if err != nil {
+			log.Printf("error when new tendermint rpc client: %v, err: %v", config.RPCEndpoint, err.Error())
+			return nil, err
+		}
+		w.rpc = client
+	}
+	for _, addr := range addresses {
+		if err := w.AddAddress(addr); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// AddAddress adds an address to the watched set.
+//
+// @param addr the 0x or cysic address to watch
+// @return an error if the address is invalid
+func (w *DepositWatcher) AddAddress(addr string) error {
+	cysicAddr, err := ConvertToCysicAddress(addr)
+	if err != nil {
+		log.Printf("error when convert addr: %v to cosmosAddr, err: %v", addr, err.Error())
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.addresses[cysicAddr] = true
+
+	return nil
+}
+
+// RemoveAddress removes an address from the watched set.
+//
+// @param addr the 0x or cysic address to stop watching
+func (w *DepositWatcher) RemoveAddress(addr string) {
+	cysicAddr, err := ConvertToCysicAddress(addr)
+	if err != nil {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.addresses, cysicAddr)
+}
+
+// Deposits returns the channel deposits are delivered on, it is closed when Run returns.
+// Every deposit read from it must be acknowledged with Ack.
+func (w *DepositWatcher) Deposits() <-chan Deposit {
+	return w.deposits
+}
+
+// Ack acknowledges a deposit received from Deposits once the consumer has handled it.
+//
+// @param deposit the handled deposit
+func (w *DepositWatcher) Ack(deposit Deposit) {
+	w.ackLock.Lock()
+	if deposit.Height == w.ackHeight && w.unacked > 0 {
+		w.unacked--
+	}
+	w.ackLock.Unlock()
+
+	select {
+	case w.acked <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes blocks until ctx is cancelled.
+//
+// New blocks are picked up from the Tendermint websocket when RPCEndpoint is set, and by
+// polling the latest height otherwise or when the websocket drops.
+//
+// @param ctx the context controlling the watcher's lifetime
+// @return the context error once the watcher stops, or an error if the start height can't be loaded
+func (w *DepositWatcher) Run(ctx context.Context) error {
+	defer close(w.deposits)
+
+	next, err := w.startHeight()
+	if err != nil {
+		return err
+	}
+
+	newBlock := w.subscribeNewBlock(ctx)
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		latest, err := w.server.GetLatestBlockHeight()
+		if err != nil {
+			log.Printf("error when get latest block height, err: %v", err.Error())
+		}
+
+		for err == nil && next <= latest {
+			if err = w.processHeight(ctx, next); err != nil {
+				log.Printf("error when process block %v, retry later, err: %v", next, err.Error())
+				break
+			}
+			next++
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-newBlock:
+			if !ok {
+				log.Printf("new block subscription closed, fall back to polling")
+				newBlock = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *DepositWatcher) startHeight() (int64, error) {
+	if w.config.HeightStore != nil {
+		height, err := w.config.HeightStore.LoadHeight()
+		if err != nil {
+			log.Printf("error when load last processed height, err: %v", err.Error())
+			return 0, err
+		}
+		if height > 0 {
+			return height + 1, nil
+		}
+	}
+
+	if w.config.StartHeight > 0 {
+		return w.config.StartHeight, nil
+	}
+
+	return w.server.GetLatestBlockHeight()
+}
+
+// subscribeNewBlock subscribes to new block headers, it returns nil if no websocket is available.
+func (w *DepositWatcher) subscribeNewBlock(ctx context.Context) <-chan int64 {
+	if w.config.RPCEndpoint == "" {
+		return nil
+	}
+
+	client, err := rpchttp.New(w.config.RPCEndpoint, "/websocket")
+	if err != nil {
+		log.Printf("error when new tendermint rpc client: %v, err: %v", w.config.RPCEndpoint, err.Error())
+		return nil
+	}
+	if err := client.Start(); err != nil {
+		log.Printf("error when start tendermint rpc client: %v, err: %v", w.config.RPCEndpoint, err.Error())
+		return nil
+	}
+
+	events, err := client.Subscribe(ctx, watcherSubscriber, tmtypes.QueryForEvent(tmtypes.EventNewBlockHeader).String())
+	if err != nil {
+		log.Printf("error when subscribe new block, err: %v", err.Error())
+		_ = client.Stop()
+		return nil
+	}
+
+	result := make(chan int64, 1)
+	go func() {
+		defer close(result)
+		defer client.Stop() //nolint: errcheck
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				header, ok := event.Data.(tmtypes.EventDataNewBlockHeader)
+				if !ok {
+					continue
+				}
+				// only the latest height matters, drop it if the watcher is still busy
+				select {
+				case result <- header.Header.Height:
+				default:
+				}
+			}
+		}
+	}()
+
+	return result
+}
+
+func (w *DepositWatcher) processHeight(ctx context.Context, height int64) error {
+	txList, err := w.blockTxs(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	w.lock.RLock()
+	deposits := make([]Deposit, 0)
+	for _, tx := range txList {
+		deposits = append(deposits, decodeDeposits(tx, w.addresses)...)
+	}
+	w.lock.RUnlock()
+
+	w.ackLock.Lock()
+	w.ackHeight = height
+	w.unacked = len(deposits)
+	w.ackLock.Unlock()
+
+	for _, deposit := range deposits {
+		select {
+		case w.deposits <- deposit:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// the height is only saved once the consumer has handled every deposit of the block
+	for {
+		w.ackLock.Lock()
+		unacked := w.unacked
+		w.ackLock.Unlock()
+		if unacked == 0 {
+			break
+		}
+
+		select {
+		case <-w.acked:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if w.config.HeightStore != nil {
+		if err := w.config.HeightStore.SaveHeight(height); err != nil {
+			return fmt.Errorf("save height %v failed, err: %v", height, err)
+		}
+	}
+
+	return nil
+}
+
+// blockTxs reads the results of the txs of a block from the tx index, or from the block and its
+// results when the node doesn't index txs.
+func (w *DepositWatcher) blockTxs(ctx context.Context, height int64) ([]*sdk.TxResponse, error) {
+	txList, err := w.server.GetTxsByHeight(height)
+	if err == nil || w.rpc == nil {
+		return txList, err
+	}
+	log.Printf("could not query txs of block %v from the tx index, read the block results: %v", height, err)
+
+	block, err := w.server.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	results, err := w.rpc.BlockResults(ctx, &height)
+	if err != nil {
+		log.Printf("could not query results of block %v: %v", height, err)
+		return nil, err
+	}
+
+	return blockTxResponses(height, block.Data.Txs, results.TxsResults)
+}
+
+// blockTxResponses pairs the txs of a block with their results.
+func blockTxResponses(height int64, txs [][]byte, results []*abci.ResponseDeliverTx) ([]*sdk.TxResponse, error) {
+	if len(txs) != len(results) {
+		return nil, fmt.Errorf("block %v has %v txs but %v results", height, len(txs), len(results))
+	}
+
+	result := make([]*sdk.TxResponse, 0, len(txs))
+	for i, txBytes := range txs {
+		txResp := &sdk.TxResponse{
+			TxHash: fmt.Sprintf("%X", tmhash.Sum(txBytes)),
+			Height: height,
+			Code:   results[i].Code,
+			RawLog: results[i].Log,
+		}
+		if txResp.Code == 0 {
+			// the log of a successful tx holds its events grouped by message
+			logs, err := sdk.ParseABCILogs(results[i].Log)
+			if err != nil {
+				return nil, fmt.Errorf("invalid log of tx %v, err: %v", txResp.TxHash, err)
+			}
+			txResp.Logs = logs
+		}
+		result = append(result, txResp)
+	}
+
+	return result, nil
+}
+
+// transferRecord is one transfer or coin_received event of a message.
+type transferRecord struct {
+	from   string
+	to     string
+	amount string
+	used   bool
+}
+
+// decodeDeposits extracts the deposits to the watched addresses from the logs of a successful transaction.
+func decodeDeposits(tx *sdk.TxResponse, addresses map[string]bool) []Deposit {
+	result := make([]Deposit, 0)
+	if tx == nil || tx.Code != 0 {
+		return result
+	}
+
+	for _, msgLog := range tx.Logs {
+		var sender string
+		var transfers, received []*transferRecord
+		for _, event := range msgLog.Events {
+			switch event.Type {
+			case sdk.EventTypeMessage:
+				for _, attr := range event.Attributes {
+					if attr.Key == sdk.AttributeKeySender && sender == "" {
+						sender = attr.Value
+					}
+				}
+			case banktypes.EventTypeTransfer:
+				transfers = splitTransferRecords(event.Attributes, banktypes.AttributeKeyRecipient, banktypes.AttributeKeySender)
+			case banktypes.EventTypeCoinReceived:
+				received = splitTransferRecords(event.Attributes, banktypes.AttributeKeyReceiver, "")
+			}
+		}
+
+		records := make([]*transferRecord, 0, len(transfers))
+		for _, transfer := range transfers {
+			if transfer.from == "" {
+				transfer.from = sender
+			}
+			records = append(records, transfer)
+		}
+		// coins received without a transfer, e.g. minted coins
+		for _, receive := range received {
+			matched := false
+			for _, transfer := range transfers {
+				if !transfer.used && transfer.to == receive.to && transfer.amount == receive.amount {
+					transfer.used = true
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				records = append(records, receive)
+			}
+		}
+
+		for _, record := range records {
+			if !addresses[record.to] {
+				continue
+			}
+
+			coins, err := sdk.ParseCoinsNormalized(record.amount)
+			if err != nil {
+				log.Printf("error when parse amount: %v of tx: %v, err: %v", record.amount, tx.TxHash, err.Error())
+				continue
+			}
+			for _, coin := range coins {
+				result = append(result, Deposit{
+					TxHash:   tx.TxHash,
+					Height:   tx.Height,
+					MsgIndex: int(msgLog.MsgIndex),
+					From:     record.from,
+					To:       record.to,
+					Denom:    coin.Denom,
+					Amount:   coin.Amount,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// splitTransferRecords splits the flattened attributes of merged events, a new record starts at every toKey.
+func splitTransferRecords(attributes []sdk.Attribute, toKey string, fromKey string) []*transferRecord {
+	result := make([]*transferRecord, 0)
+	var current *transferRecord
+	for _, attr := range attributes {
+		switch attr.Key {
+		case toKey:
+			current = &transferRecord{to: attr.Value}
+			result = append(result, current)
+		case fromKey:
+			if current != nil {
+				current.from = attr.Value
+			}
+		case sdk.AttributeKeyAmount:
+			if current != nil {
+				current.amount = attr.Value
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,227 @@
+package gosdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkTx "github.com/cosmos/cosmos-sdk/types/tx"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newDepositLog(msgIndex uint32, events ...sdk.StringEvent) sdk.ABCIMessageLog {
+	return sdk.ABCIMessageLog{MsgIndex: msgIndex, Events: events}
+}
+
+func newDepositEvent(eventType string, keyValues ...string) sdk.StringEvent {
+	event := sdk.StringEvent{Type: eventType}
+	for i := 0; i < len(keyValues); i += 2 {
+		event.Attributes = append(event.Attributes, sdk.Attribute{Key: keyValues[i], Value: keyValues[i+1]})
+	}
+
+	return event
+}
+
+func TestDecodeDeposits(t *testing.T) {
+	watched := map[string]bool{"alice": true, "bob": true}
+	sendLog := newDepositLog(0,
+		newDepositEvent("coin_received", "receiver", "alice", "amount", "100CYS"),
+		newDepositEvent("message", "action", "/cosmos.bank.v1beta1.MsgSend", "sender", "carol"),
+		newDepositEvent("transfer", "recipient", "alice", "sender", "carol", "amount", "100CYS"),
+	)
+
+	tests := []struct {
+		name string
+		tx   *sdk.TxResponse
+		want []Deposit
+	}{
+		{name: "nil tx", want: []Deposit{}},
+		{name: "failed tx", tx: &sdk.TxResponse{Code: 5, Logs: sdk.ABCIMessageLogs{sendLog}}, want: []Deposit{}},
+		{
+			name: "send",
+			tx:   &sdk.TxResponse{TxHash: "A1", Height: 10, Logs: sdk.ABCIMessageLogs{sendLog}},
+			want: []Deposit{{TxHash: "A1", Height: 10, From: "carol", To: "alice", Denom: "CYS", Amount: math.NewInt(100)}},
+		},
+		{
+			name: "unwatched recipient",
+			tx: &sdk.TxResponse{Logs: sdk.ABCIMessageLogs{newDepositLog(0,
+				newDepositEvent("message", "sender", "carol"),
+				newDepositEvent("transfer", "recipient", "dave", "sender", "carol", "amount", "100CYS"),
+			)}},
+			want: []Deposit{},
+		},
+		{
+			name: "multi send outputs take the message sender",
+			tx: &sdk.TxResponse{TxHash: "B2", Height: 11, Logs: sdk.ABCIMessageLogs{newDepositLog(1,
+				newDepositEvent("coin_received", "receiver", "alice", "amount", "5CGT", "receiver", "dave", "amount", "6CGT", "receiver", "bob", "amount", "7CGT,8CYS"),
+				newDepositEvent("message", "sender", "carol"),
+				newDepositEvent("transfer", "recipient", "alice", "amount", "5CGT", "recipient", "dave", "amount", "6CGT", "recipient", "bob", "amount", "7CGT,8CYS"),
+			)}},
+			want: []Deposit{
+				{TxHash: "B2", Height: 11, MsgIndex: 1, From: "carol", To: "alice", Denom: "CGT", Amount: math.NewInt(5)},
+				{TxHash: "B2", Height: 11, MsgIndex: 1, From: "carol", To: "bob", Denom: "CGT", Amount: math.NewInt(7)},
+				{TxHash: "B2", Height: 11, MsgIndex: 1, From: "carol", To: "bob", Denom: "CYS", Amount: math.NewInt(8)},
+			},
+		},
+		{
+			name: "minted coins have no sender",
+			tx: &sdk.TxResponse{TxHash: "C3", Height: 12, Logs: sdk.ABCIMessageLogs{newDepositLog(0,
+				newDepositEvent("coin_received", "receiver", "bob", "amount", "9CGT"),
+				newDepositEvent("message", "sender", "bob"),
+			)}},
+			want: []Deposit{{TxHash: "C3", Height: 12, To: "bob", Denom: "CGT", Amount: math.NewInt(9)}},
+		},
+		{
+			name: "invalid amount is skipped",
+			tx: &sdk.TxResponse{Logs: sdk.ABCIMessageLogs{newDepositLog(0,
+				newDepositEvent("message", "sender", "carol"),
+				newDepositEvent("transfer", "recipient", "alice", "sender", "carol", "amount", "-1CYS"),
+			)}},
+			want: []Deposit{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeDeposits(tt.tx, watched)
+			if len(got) != len(tt.want) {
+				t.Fatalf("decodeDeposits() = %+v, want %+v", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].TxHash != want.TxHash || got[i].Height != want.Height || got[i].MsgIndex != want.MsgIndex ||
+					got[i].From != want.From || got[i].To != want.To || got[i].Denom != want.Denom || !got[i].Amount.Equal(want.Amount) {
+					t.Errorf("deposit %d = %+v, want %+v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBlockTxResponses(t *testing.T) {
+	logs := sdk.ABCIMessageLogs{newDepositLog(0,
+		newDepositEvent("message", "sender", "carol"),
+		newDepositEvent("transfer", "recipient", "alice", "sender", "carol", "amount", "100CYS"),
+	)}
+	bz, err := json.Marshal(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := [][]byte{[]byte("tx-ok"), []byte("tx-failed")}
+	results := []*abci.ResponseDeliverTx{{Log: string(bz)}, {Code: 5, Log: "insufficient funds"}}
+
+	got, err := blockTxResponses(20, txs, results)
+	if err != nil {
+		t.Fatalf("blockTxResponses() err = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("blockTxResponses() = %+v, want 2 txs", got)
+	}
+	if got[0].TxHash != fmt.Sprintf("%X", tmhash.Sum(txs[0])) || got[0].Height != 20 || len(got[0].Logs) != 1 {
+		t.Errorf("tx 0 = %+v, want the hash, height and logs of the block", got[0])
+	}
+	if got[1].Code != 5 || len(got[1].Logs) != 0 {
+		t.Errorf("tx 1 = %+v, want the failed result without logs", got[1])
+	}
+	if deposits := decodeDeposits(got[0], map[string]bool{"alice": true}); len(deposits) != 1 || !deposits[0].Amount.Equal(math.NewInt(100)) {
+		t.Errorf("deposits = %+v, want 100CYS to alice", deposits)
+	}
+
+	if _, err := blockTxResponses(20, txs, results[:1]); err == nil {
+		t.Errorf("blockTxResponses() accepted fewer results than txs")
+	}
+}
+
+// fakeTxIndex serves the txs of every height from the tx index.
+type fakeTxIndex struct {
+	sdkTx.UnimplementedServiceServer
+	txs map[int64][]*sdk.TxResponse
+}
+
+func (f *fakeTxIndex) GetTxsEvent(_ context.Context, req *sdkTx.GetTxsEventRequest) (*sdkTx.GetTxsEventResponse, error) {
+	var height int64
+	if _, err := fmt.Sscanf(req.Events[0], "tx.height=%d", &height); err != nil {
+		return nil, err
+	}
+
+	return &sdkTx.GetTxsEventResponse{TxResponses: f.txs[height], Total: uint64(len(f.txs[height]))}, nil
+}
+
+// memoryHeightStore is a HeightStore kept in memory.
+type memoryHeightStore struct {
+	lock   sync.Mutex
+	height int64
+}
+
+func (m *memoryHeightStore) LoadHeight() (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.height, nil
+}
+
+func (m *memoryHeightStore) SaveHeight(height int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.height = height
+	return nil
+}
+
+func TestDepositWatcherAck(t *testing.T) {
+	alice := sdk.AccAddress([]byte("deposit-watcher-alice")).String()
+	transfer := func(hash string, amount string) *sdk.TxResponse {
+		return &sdk.TxResponse{TxHash: hash, Height: 30, Logs: sdk.ABCIMessageLogs{newDepositLog(0,
+			newDepositEvent("message", "sender", "carol"),
+			newDepositEvent("transfer", "recipient", alice, "sender", "carol", "amount", amount),
+		)}}
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	sdkTx.RegisterServiceServer(grpcServer, &fakeTxIndex{txs: map[int64][]*sdk.TxResponse{30: {transfer("A1", "1CYS"), transfer("A2", "2CYS")}}})
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	store := &memoryHeightStore{height: 29}
+	watcher, err := (&Server{EndPoint: "bufnet", Conn: conn}).NewDepositWatcher([]string{alice}, DepositWatcherConfig{HeightStore: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.processHeight(context.Background(), 30) }()
+
+	first := <-watcher.Deposits()
+	second := <-watcher.Deposits()
+	watcher.Ack(first)
+	select {
+	case err := <-done:
+		t.Fatalf("processHeight() returned %v before every deposit was acknowledged", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if height, _ := store.LoadHeight(); height != 29 {
+		t.Fatalf("saved height %v before every deposit was acknowledged", height)
+	}
+
+	watcher.Ack(second)
+	if err := <-done; err != nil {
+		t.Fatalf("processHeight() err = %v", err)
+	}
+	if height, _ := store.LoadHeight(); height != 30 {
+		t.Errorf("saved height = %v, want 30", height)
+	}
+}